@@ -0,0 +1,71 @@
+// plugin-example 进程外活动插件示例：go build -o plugins/example ./cmd/plugin-example，
+// 并在 worker 配置中设置 plugins.dir: "plugins"
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"zebra-workflow/pkg/plugin"
+)
+
+func main() {
+	plugin.Serve(
+		plugin.Activity{
+			Name:         "Echo",
+			Description:  "返回参数 message，upper 为 true 时转为大写",
+			InputSchema:  json.RawMessage(`{"type":"object","properties":{"message":{"type":"string"},"upper":{"type":"string","enum":["true","false"]}},"required":["message"]}`),
+			OutputSchema: json.RawMessage(`{"type":"object","properties":{"message":{"type":"string"}}}`),
+			Execute:      echo,
+		},
+		plugin.Activity{
+			Name:         "Countdown",
+			Description:  "每秒发送一次心跳，从 from 倒数到 0；重试时从上次心跳继续",
+			InputSchema:  json.RawMessage(`{"type":"object","properties":{"from":{"type":"string","pattern":"^[0-9]+$"}},"required":["from"]}`),
+			OutputSchema: json.RawMessage(`{"type":"object","properties":{"done":{"type":"boolean"},"resumedAt":{"type":"integer"}}}`),
+			Execute:      countdown,
+		},
+	)
+}
+
+func echo(_ context.Context, req *plugin.Request) (interface{}, error) {
+	msg, ok := req.Input["message"]
+	if !ok {
+		return nil, &plugin.Error{Type: "InvalidArgument", Message: "message is required", NonRetryable: true}
+	}
+	if req.Input["upper"] == "true" {
+		msg = strings.ToUpper(msg)
+	}
+	return map[string]string{"message": msg}, nil
+}
+
+func countdown(ctx context.Context, req *plugin.Request) (interface{}, error) {
+	n, err := strconv.Atoi(req.Input["from"])
+	if err != nil || n < 0 {
+		return nil, &plugin.Error{Type: "InvalidArgument", Message: fmt.Sprintf("invalid from %q", req.Input["from"]), NonRetryable: true}
+	}
+	resumedAt := -1
+	if len(req.HeartbeatDetails) > 0 {
+		if err := json.Unmarshal(req.HeartbeatDetails, &n); err != nil {
+			return nil, err
+		}
+		resumedAt = n
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for ; n > 0; n-- {
+		if err := req.Heartbeat(n); err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return map[string]interface{}{"done": true, "resumedAt": resumedAt}, nil
+}
@@ -13,9 +13,13 @@ import (
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/rest"
 
+	"zebra-workflow/internal/auth"
 	"zebra-workflow/internal/handler"
 	logger "zebra-workflow/internal/log"
+	"zebra-workflow/internal/monitor"
+	"zebra-workflow/internal/pluginhost"
 	"zebra-workflow/internal/temporal"
+	"zebra-workflow/internal/tracing"
 )
 
 // Config 映射 configs/config.yaml 中需要的部分
@@ -33,6 +37,16 @@ type Config struct {
 		Encoding string   `yaml:"encoding" json:"encoding"`
 		Outputs  []string `yaml:"outputs" json:"outputs"`
 	} `yaml:"logging" json:"logging"`
+
+	Monitor struct {
+		PrometheusAddr string `yaml:"prometheusAddr" json:"prometheusAddr,optional"`
+	} `yaml:"monitor" json:"monitor,optional"`
+
+	Tracing tracing.Config `yaml:"tracing" json:"tracing,optional"`
+
+	Auth auth.Config `yaml:"auth" json:"auth,optional"`
+
+	Plugins pluginhost.Config `yaml:"plugins" json:"plugins,optional"`
 }
 
 func main() {
@@ -52,6 +66,20 @@ func main() {
 	defer logger.Close()
 	logger.Sugar.Infof("starting %s", cfg.App.Name)
 
+	// expose /metrics (HTTP + Temporal SDK metrics)
+	monitor.Serve(cfg.Monitor.PrometheusAddr)
+
+	// tracing must be initialised before the temporal client so its interceptor uses the configured provider
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, cfg.App.Name)
+	if err != nil {
+		logger.Sugar.Fatalf("tracing init failed: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdownTracing(ctx)
+	}()
+
 	// 3. parse addr
 	addr := cfg.HTTP.Addr
 	if strings.TrimSpace(addr) == "" {
@@ -92,13 +120,27 @@ func main() {
 	}
 	defer tc.Close()
 
-	// 5. register handlers (handler uses temporal client)
-	handler.RegisterRoutes(server, tc)
+	// 5. register handlers (handler uses temporal client); authn is nil when auth is disabled
+	authn, err := auth.New(cfg.Auth)
+	if err != nil {
+		logger.Sugar.Fatalf("auth init failed: %v", err)
+	}
+	if authn == nil {
+		logger.Sugar.Warn("HTTP API authentication is disabled")
+	}
+	// 插件活动登记到活动目录（GET /v1/activities、DSL 静态检查）；server 不执行插件活动，读取描述后即结束插件进程
+	if plugins, err := pluginhost.Discover(cfg.Plugins); err != nil {
+		logger.Sugar.Errorw("unable to read activity plugins, their activities are not listed", "err", err)
+	} else {
+		plugins.RegisterCatalog()
+		plugins.Close()
+	}
+	handler.RegisterRoutes(server, tc, authn)
 
 	fullAddr := fmt.Sprintf("%s:%d", host, port)
 	logger.Sugar.Infof("HTTP server started on %s", fullAddr)
 
-	// 6. start config watcher for hot reload of logging config
+	// 6. start config watcher for hot reload of logging config and temporal tls certificates
 	go func() {
 		watcher, err := fsnotify.NewWatcher()
 		if err != nil {
@@ -111,10 +153,19 @@ func main() {
 			logger.Sugar.Errorw("failed to add config file to watcher", "path", cfgPath, "err", err)
 			return
 		}
+		// certificates are usually rotated by replacing the file, so the watch is re-added after each reload
+		watchTLS := func() {
+			for _, f := range temporal.TLSFiles() {
+				if err := watcher.Add(f); err != nil {
+					logger.Sugar.Warnw("failed to add tls file to watcher", "path", f, "err", err)
+				}
+			}
+		}
+		watchTLS()
 
 		debounce := time.NewTimer(0)
 		<-debounce.C // drain
-		var pending bool
+		var pending, tlsPending bool
 
 		for {
 			select {
@@ -122,15 +173,23 @@ func main() {
 				if !ok {
 					return
 				}
-				// watch for write/create/rename
-				if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				// certificate files may be replaced (remove + create), the config file is only reloaded on write/create/rename
+				tlsEvent := temporal.IsTLSFile(ev.Name) && ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) != 0
+				configEvent := !tlsEvent && ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0
+				if tlsEvent || configEvent {
 					// debounce rapid events
-					if !pending {
+					if !pending && !tlsPending {
 						debounce.Reset(200 * time.Millisecond)
-						pending = true
 					}
+					tlsPending = tlsPending || tlsEvent
+					pending = pending || configEvent
 				}
 			case <-debounce.C:
+				if tlsPending {
+					temporal.ReloadTLS()
+					watchTLS()
+					tlsPending = false
+				}
 				if pending {
 					var newCfg Config
 					if err := conf.Load(cfgPath, &newCfg); err != nil {
@@ -3,15 +3,24 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/zeromicro/go-zero/core/conf"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/worker"
 
 	"zebra-workflow/internal/activity"
+	"zebra-workflow/internal/catalog"
 	logger "zebra-workflow/internal/log"
+	"zebra-workflow/internal/monitor"
+	"zebra-workflow/internal/pluginhost"
+	"zebra-workflow/internal/secrets"
 	"zebra-workflow/internal/temporal"
+	"zebra-workflow/internal/tracing"
 	"zebra-workflow/internal/workflow"
 )
 
@@ -21,9 +30,32 @@ type WorkerConfig struct {
 		Encoding string   `yaml:"encoding" json:"encoding"`
 		Outputs  []string `yaml:"outputs" json:"outputs"`
 	} `yaml:"logging" json:"logging"`
+
+	Monitor struct {
+		PrometheusAddr       string `yaml:"prometheusAddr" json:"prometheusAddr,optional"`
+		WorkerPrometheusAddr string `yaml:"workerPrometheusAddr" json:"workerPrometheusAddr,optional"`
+	} `yaml:"monitor" json:"monitor,optional"`
+
+	Tracing tracing.Config `yaml:"tracing" json:"tracing,optional"`
+
+	Secrets secrets.Config `yaml:"secrets" json:"secrets,optional"`
+
+	SMTP activity.SMTPConfig `yaml:"smtp" json:"smtp,optional"`
+
+	// Databases 连接名 -> SQLQuery 活动使用的数据库连接
+	Databases map[string]activity.DatabaseConfig `yaml:"databases" json:"databases,optional"`
+
+	Scripts activity.ScriptConfig `yaml:"scripts" json:"scripts,optional"`
+
+	Plugins pluginhost.Config `yaml:"plugins" json:"plugins,optional"`
 }
 
 func main() {
+	// Script 活动在 worker 自身启动的子进程中执行脚本
+	if activity.IsScriptSandbox() {
+		os.Exit(activity.RunScriptSandbox())
+	}
+
 	const cfgPath = "configs/config.yaml"
 
 	// load config to get logging level
@@ -38,7 +70,33 @@ func main() {
 	}
 	defer logger.Close()
 
-	// start watcher for config reload (logging)
+	// expose /metrics (Temporal SDK + DSL metrics); workerPrometheusAddr avoids a port clash when server and worker
+	// run on the same host
+	metricsAddr := cfg.Monitor.WorkerPrometheusAddr
+	if metricsAddr == "" {
+		metricsAddr = cfg.Monitor.PrometheusAddr
+	}
+	monitor.Serve(metricsAddr)
+
+	// tracing must be initialised before the temporal client and worker so their interceptors are installed
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, "zebra-worker")
+	if err != nil {
+		logger.Sugar.Fatalf("tracing init failed: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdownTracing(ctx)
+	}()
+
+	// init temporal client
+	tc, err := temporal.NewClientFromConfig(cfgPath)
+	if err != nil {
+		logger.Sugar.Fatalf("temporal client init failed: %v", err)
+	}
+	defer tc.Close()
+
+	// start watcher for config reload (logging) and temporal tls certificates
 	go func() {
 		watcher, err := fsnotify.NewWatcher()
 		if err != nil {
@@ -51,10 +109,19 @@ func main() {
 			logger.Sugar.Errorw("failed to add config file to watcher", "path", cfgPath, "err", err)
 			return
 		}
+		// certificates are usually rotated by replacing the file, so the watch is re-added after each reload
+		watchTLS := func() {
+			for _, f := range temporal.TLSFiles() {
+				if err := watcher.Add(f); err != nil {
+					logger.Sugar.Warnw("failed to add tls file to watcher", "path", f, "err", err)
+				}
+			}
+		}
+		watchTLS()
 
 		debounce := time.NewTimer(0)
 		<-debounce.C
-		var pending bool
+		var pending, tlsPending bool
 
 		for {
 			select {
@@ -62,13 +129,23 @@ func main() {
 				if !ok {
 					return
 				}
-				if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
-					if !pending {
+				// certificate files may be replaced (remove + create), the config file is only reloaded on write/create/rename
+				tlsEvent := temporal.IsTLSFile(ev.Name) && ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) != 0
+				configEvent := !tlsEvent && ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0
+				if tlsEvent || configEvent {
+					// debounce rapid events
+					if !pending && !tlsPending {
 						debounce.Reset(200 * time.Millisecond)
-						pending = true
 					}
+					tlsPending = tlsPending || tlsEvent
+					pending = pending || configEvent
 				}
 			case <-debounce.C:
+				if tlsPending {
+					temporal.ReloadTLS()
+					watchTLS()
+					tlsPending = false
+				}
 				if pending {
 					var newCfg WorkerConfig
 					if err := conf.Load(cfgPath, &newCfg); err != nil {
@@ -91,15 +168,88 @@ func main() {
 		}
 	}()
 
-	// init temporal client
-	tc, err := temporal.NewClientFromConfig(cfgPath)
+	// create one worker for the default namespace and one per configured tenant (namespace + task queue)
+	workerInterceptors, err := tracing.WorkerInterceptors()
 	if err != nil {
-		logger.Sugar.Fatalf("temporal client init failed: %v", err)
+		logger.Sugar.Fatalf("tracing interceptor init failed: %v", err)
 	}
-	defer tc.Close()
+	// secret references in activity arguments are resolved here, never in the workflow or the server
+	secretProvider, err := secrets.New(cfg.Secrets)
+	if err != nil {
+		logger.Sugar.Fatalf("secret provider init failed: %v", err)
+	}
+	secretInterceptor, err := secrets.NewInterceptor(secretProvider, cfg.Secrets.Allow)
+	if err != nil {
+		logger.Sugar.Fatalf("secret allowlist init failed: %v", err)
+	}
+	workerInterceptors = append(workerInterceptors, secretInterceptor)
+	// 按注册的 OutputSchema 检查 workflow 返回值
+	schemaInterceptor, err := workflow.NewSchemaInterceptor()
+	if err != nil {
+		logger.Sugar.Fatalf("workflow schema init failed: %v", err)
+	}
+	workerInterceptors = append(workerInterceptors, schemaInterceptor)
+	// ExtractHTML 等活动的中间结果保存在 blob store，重试时读回
+	activity.SetProgressStore(tc.BlobStore())
+	emailSender, err := activity.NewEmailSender(cfg.SMTP, tc.BlobStore())
+	if err != nil {
+		logger.Sugar.Fatalf("email activity init failed: %v", err)
+	}
+	sqlRunner, err := activity.NewSQLRunner(cfg.Databases)
+	if err != nil {
+		logger.Sugar.Fatalf("sql activity init failed: %v", err)
+	}
+	defer sqlRunner.Close()
+	scriptRunner, err := activity.NewScriptRunner(cfg.Scripts)
+	if err != nil {
+		logger.Sugar.Fatalf("script activity init failed: %v", err)
+	}
+	plugins, err := pluginhost.Discover(cfg.Plugins)
+	if err != nil {
+		logger.Sugar.Fatalf("activity plugins init failed: %v", err)
+	}
+	defer plugins.Close()
+	// 插件活动登记到活动目录后与内置活动一起注册，与内置活动重名时跳过
+	plugins.RegisterCatalog()
+	builtins := builtinActivities{email: emailSender, sql: sqlRunner, script: scriptRunner}
+	workers := []worker.Worker{newWorker(tc, workerInterceptors, builtins)}
+	for _, name := range tc.Tenants() {
+		ttc, err := tc.ForTenant(name)
+		if err != nil {
+			logger.Sugar.Fatalf("temporal client init failed for tenant %s: %v", name, err)
+		}
+		logger.Sugar.Infow("starting tenant worker", "tenant", name, "taskQueue", ttc.DefaultQueue())
+		workers = append(workers, newWorker(ttc, workerInterceptors, builtins))
+	}
+
+	// start workers
+	for _, w := range workers {
+		if err := w.Start(); err != nil {
+			logger.Sugar.Fatalf("worker start failed: %v", err)
+		}
+		defer w.Stop()
+	}
+	fmt.Println("Temporal worker started")
+
+	// return on SIGINT / SIGTERM so the defers above run: stop the workers, then close plugins, database pools and tracing
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// garbage-collect offloaded payloads once the workflows that wrote them have closed past retention
+	go tc.RunBlobGC(ctx)
+	<-ctx.Done()
+	logger.Sugar.Info("shutting down worker")
+}
 
-	// create worker using exported client
-	w := worker.New(tc.Client(), tc.DefaultQueue(), worker.Options{})
+// builtinActivities 依赖 worker 配置的内置活动，所有 namespace 的 worker 共享
+type builtinActivities struct {
+	email  *activity.EmailSender
+	sql    *activity.SQLRunner
+	script *activity.ScriptRunner
+}
+
+// newWorker 在 tc 的 namespace / task queue 上创建 worker 并注册所有 workflow 和活动
+func newWorker(tc *temporal.ClientWrapper, interceptors []interceptor.WorkerInterceptor, builtins builtinActivities) worker.Worker {
+	w := worker.New(tc.Client(), tc.DefaultQueue(), worker.Options{Interceptors: interceptors})
 
 	// register workflows and activities into the worker
 	for _, wf := range workflow.ListRegistered() {
@@ -108,20 +258,17 @@ func main() {
 		w.RegisterWorkflowWithOptions(workflowFunc, workflow.GetRegisterOptions(wf))
 	}
 
-	act := activity.NewActivityImpl()
-	w.RegisterActivity(act)
-	// 注册 DSL 示例活动（函数包装），使活动类型名为 "SampleActivity" 等，匹配 DSL YAML 中的 a.Name
-	w.RegisterActivity(activity.SampleActivity)
-	w.RegisterActivity(activity.SampleActivitySendEmail)
-	w.RegisterActivity(activity.SampleActivitySendEmailTyped)
-	w.RegisterActivity(activity.GetTitle)
-
-	// start worker
-	if err := w.Start(); err != nil {
-		logger.Sugar.Fatalf("worker start failed: %v", err)
+	// 活动目录中的活动（internal/activity 登记的内置活动和插件活动），依赖配置的活动由 builtins 提供实现
+	impls := map[string]interface{}{
+		"SendEmail": builtins.email.SendEmail,
+		"SQLQuery":  builtins.sql.SQLQuery,
+		"Script":    builtins.script.Script,
+		// 旧的邮件示例活动名，参数 body 等同于 text
+		"SampleActivitySendEmail":      builtins.email.SendEmail,
+		"SampleActivitySendEmailTyped": builtins.email.SendEmail,
 	}
-	fmt.Println("Temporal worker started")
-	select {
-	case <-context.Background().Done():
+	if err := catalog.RegisterWorker(w, impls); err != nil {
+		logger.Sugar.Fatalf("register activities failed: %v", err)
 	}
+	return w
 }
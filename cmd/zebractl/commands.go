@@ -0,0 +1,412 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// kvFlag 可重复的 -var k=v 参数
+type kvFlag map[string]string
+
+func (f kvFlag) String() string { return "" }
+
+func (f kvFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	f[k] = v
+	return nil
+}
+
+// listFlag 可重复的字符串参数（如 -cron）
+type listFlag []string
+
+func (f *listFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *listFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+// readJSONFile 读取 JSON 文件为对象
+func readJSONFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// buildInput 组合 workflow input：-file 优先，其次 -input，最后用 -var 覆盖 DSL 的 Variables
+func buildInput(file, raw string, vars kvFlag) (map[string]interface{}, error) {
+	input := map[string]interface{}{}
+	switch {
+	case file != "":
+		v, err := readJSONFile(file)
+		if err != nil {
+			return nil, err
+		}
+		input = v
+	case raw != "":
+		if err := json.Unmarshal([]byte(raw), &input); err != nil {
+			return nil, fmt.Errorf("parse -input: %w", err)
+		}
+	}
+	if len(vars) > 0 {
+		variables, _ := input["Variables"].(map[string]interface{})
+		if variables == nil {
+			variables = map[string]interface{}{}
+		}
+		for k, v := range vars {
+			variables[k] = v
+		}
+		input["Variables"] = variables
+	}
+	return input, nil
+}
+
+// oneArg 解析只有一个位置参数（如 workflowId）的子命令
+func oneArg(name string, args []string) (string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() != 1 {
+		return "", fmt.Errorf("usage: zebractl %s", commands[name].usage)
+	}
+	return fs.Arg(0), nil
+}
+
+func runStart(c *apiClient, p *printer, args []string) error {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	name := fs.String("name", "DSLWorkflow", "workflow name")
+	version := fs.String("version", "v1", "workflow version")
+	file := fs.String("file", "", "JSON file with the workflow input (DSL for DSLWorkflow)")
+	raw := fs.String("input", "", "workflow input as inline JSON")
+	vars := kvFlag{}
+	fs.Var(vars, "var", "DSL variable key=value, repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	input, err := buildInput(*file, *raw, vars)
+	if err != nil {
+		return err
+	}
+	var resp map[string]interface{}
+	body := map[string]interface{}{"name": *name, "version": *version, "input": input}
+	if err := c.do(http.MethodPost, "/v1/workflow/start", nil, body, &resp); err != nil {
+		return err
+	}
+	return p.print(resp)
+}
+
+func runStatus(c *apiClient, p *printer, args []string) error {
+	wid, err := oneArg("status", args)
+	if err != nil {
+		return err
+	}
+	var resp map[string]interface{}
+	if err := c.do(http.MethodGet, "/v1/workflow/"+url.PathEscape(wid)+"/status", nil, nil, &resp); err != nil {
+		return err
+	}
+	return p.print(resp)
+}
+
+func runResult(c *apiClient, p *printer, args []string) error {
+	wid, err := oneArg("result", args)
+	if err != nil {
+		return err
+	}
+	var resp map[string]interface{}
+	if err := c.do(http.MethodGet, "/v1/workflow/"+url.PathEscape(wid)+"/result", nil, nil, &resp); err != nil {
+		return err
+	}
+	return p.print(resp)
+}
+
+func runSignal(c *apiClient, p *printer, args []string) error {
+	fs := flag.NewFlagSet("signal", flag.ContinueOnError)
+	raw := fs.String("payload", "", "signal payload as inline JSON object")
+	file := fs.String("payload-file", "", "JSON file with the signal payload")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: zebractl %s", commands["signal"].usage)
+	}
+	payload, err := buildInput(*file, *raw, nil)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{"signalName": fs.Arg(1), "payload": payload}
+	if err := c.do(http.MethodPost, "/v1/workflow/"+url.PathEscape(fs.Arg(0))+"/signal", nil, body, nil); err != nil {
+		return err
+	}
+	return p.print(map[string]interface{}{"workflowId": fs.Arg(0), "signal": fs.Arg(1), "sent": true})
+}
+
+func runCancel(c *apiClient, p *printer, args []string) error {
+	wid, err := oneArg("cancel", args)
+	if err != nil {
+		return err
+	}
+	if err := c.do(http.MethodPost, "/v1/workflow/"+url.PathEscape(wid)+"/cancel", nil, nil, nil); err != nil {
+		return err
+	}
+	return p.print(map[string]interface{}{"workflowId": wid, "cancelRequested": true})
+}
+
+func runTerminate(c *apiClient, p *printer, args []string) error {
+	fs := flag.NewFlagSet("terminate", flag.ContinueOnError)
+	reason := fs.String("reason", "", "reason recorded in the workflow history")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: zebractl %s", commands["terminate"].usage)
+	}
+	wid := fs.Arg(0)
+	body := map[string]interface{}{"reason": *reason}
+	if err := c.do(http.MethodPost, "/v1/workflow/"+url.PathEscape(wid)+"/terminate", nil, body, nil); err != nil {
+		return err
+	}
+	return p.print(map[string]interface{}{"workflowId": wid, "terminated": true})
+}
+
+func runReset(c *apiClient, p *printer, args []string) error {
+	fs := flag.NewFlagSet("reset", flag.ContinueOnError)
+	resetType := fs.String("type", "last", "reset to the first or last completed workflow task")
+	event := fs.Int64("event", 0, "WorkflowTaskCompleted event id to reset to (overrides -type)")
+	reason := fs.String("reason", "", "reason recorded in the workflow history")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: zebractl %s", commands["reset"].usage)
+	}
+	wid := fs.Arg(0)
+	body := map[string]interface{}{"type": *resetType, "eventId": *event, "reason": *reason}
+	var resp map[string]interface{}
+	if err := c.do(http.MethodPost, "/v1/workflow/"+url.PathEscape(wid)+"/reset", nil, body, &resp); err != nil {
+		return err
+	}
+	return p.print(resp)
+}
+
+func runList(c *apiClient, p *printer, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	query := fs.String("query", "", "visibility query, e.g. WorkflowType='DSLWorkflow'")
+	pageSize := fs.Int("page-size", 20, "page size")
+	pageToken := fs.String("page-token", "", "nextPageToken of the previous page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	q := url.Values{}
+	if *query != "" {
+		q.Set("query", *query)
+	}
+	q.Set("pageSize", strconv.Itoa(*pageSize))
+	if *pageToken != "" {
+		q.Set("nextPageToken", *pageToken)
+	}
+	var resp struct {
+		Executions    []map[string]interface{} `json:"executions"`
+		NextPageToken string                   `json:"nextPageToken,omitempty"`
+	}
+	if err := c.do(http.MethodGet, "/v1/workflows", q, nil, &resp); err != nil {
+		return err
+	}
+	if err := p.list(resp, resp.Executions, []string{"workflowId", "type", "status", "startTime", "closeTime", "startedBy"}); err != nil {
+		return err
+	}
+	if p.format == "table" && resp.NextPageToken != "" {
+		fmt.Fprintf(p.w, "\nnext page: -page-token %s\n", resp.NextPageToken)
+	}
+	return nil
+}
+
+func runValidate(c *apiClient, p *printer, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	file := fs.String("file", "", "JSON file with the DSL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("usage: zebractl %s", commands["validate"].usage)
+	}
+	input, err := readJSONFile(*file)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Valid    bool                     `json:"valid"`
+		Problems []map[string]interface{} `json:"problems,omitempty"`
+	}
+	if err := c.do(http.MethodPost, "/v1/dsl/validate", nil, map[string]interface{}{"input": input}, &resp); err != nil {
+		return err
+	}
+	if p.format == "table" && resp.Valid {
+		fmt.Fprintln(p.w, "valid")
+		return nil
+	}
+	if err := p.list(resp, resp.Problems, []string{"statement", "message"}); err != nil {
+		return err
+	}
+	if !resp.Valid {
+		return errors.New("dsl is invalid")
+	}
+	return nil
+}
+
+func runActivities(c *apiClient, p *printer, args []string) error {
+	q := url.Values{}
+	if len(args) > 0 {
+		q.Set("name", args[0])
+	}
+	var resp struct {
+		Activities []map[string]interface{} `json:"activities"`
+	}
+	if err := c.do(http.MethodGet, "/v1/activities", q, nil, &resp); err != nil {
+		return err
+	}
+	// 指定活动时输出完整描述（含 schema）
+	if len(args) > 0 {
+		if len(resp.Activities) == 0 {
+			return fmt.Errorf("activity %s not found", args[0])
+		}
+		return p.json(resp.Activities[0])
+	}
+	return p.list(resp, resp.Activities, []string{"name", "version", "description", "deprecated"})
+}
+
+func runSimulate(c *apiClient, p *printer, args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	file := fs.String("file", "", "JSON file with the DSL")
+	mocksFile := fs.String("mocks", "", "JSON file with an array of activity mocks")
+	vars := kvFlag{}
+	fs.Var(vars, "var", "DSL variable key=value, repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("usage: zebractl %s", commands["simulate"].usage)
+	}
+	input, err := readJSONFile(*file)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{"input": input, "variables": map[string]string(vars)}
+	if *mocksFile != "" {
+		data, err := os.ReadFile(*mocksFile)
+		if err != nil {
+			return err
+		}
+		var mocks []interface{}
+		if err := json.Unmarshal(data, &mocks); err != nil {
+			return fmt.Errorf("parse %s: %w", *mocksFile, err)
+		}
+		body["mocks"] = mocks
+	}
+	var resp struct {
+		Trace    []map[string]interface{} `json:"trace"`
+		Bindings map[string]interface{}   `json:"bindings"`
+		Output   interface{}              `json:"output,omitempty"`
+		Error    string                   `json:"error,omitempty"`
+	}
+	if err := c.do(http.MethodPost, "/v1/dsl/simulate", nil, body, &resp); err != nil {
+		return err
+	}
+	if p.format == "json" {
+		return p.json(resp)
+	}
+	if err := p.list(resp, resp.Trace, []string{"statement", "activity", "call", "output", "error"}); err != nil {
+		return err
+	}
+	fmt.Fprintln(p.w)
+	summary := map[string]interface{}{"bindings": resp.Bindings, "output": resp.Output}
+	if resp.Error != "" {
+		summary["error"] = resp.Error
+	}
+	return p.print(summary)
+}
+
+func runSchedule(c *apiClient, p *printer, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: zebractl %s", commands["schedule"].usage)
+	}
+	sub, args := args[0], args[1:]
+	switch sub {
+	case "create":
+		fs := flag.NewFlagSet("schedule create", flag.ContinueOnError)
+		id := fs.String("id", "", "schedule id")
+		name := fs.String("name", "DSLWorkflow", "workflow name")
+		version := fs.String("version", "v1", "workflow version")
+		file := fs.String("file", "", "JSON file with the workflow input")
+		raw := fs.String("input", "", "workflow input as inline JSON")
+		interval := fs.String("interval", "", "run every interval, e.g. 1h")
+		paused := fs.Bool("paused", false, "create the schedule paused")
+		note := fs.String("note", "", "note")
+		var cron listFlag
+		fs.Var(&cron, "cron", "cron expression, repeatable")
+		vars := kvFlag{}
+		fs.Var(vars, "var", "DSL variable key=value, repeatable")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		input, err := buildInput(*file, *raw, vars)
+		if err != nil {
+			return err
+		}
+		body := map[string]interface{}{
+			"id": *id, "name": *name, "version": *version, "input": input,
+			"cron": []string(cron), "interval": *interval, "paused": *paused, "note": *note,
+		}
+		if err := c.do(http.MethodPost, "/v1/schedules", nil, body, nil); err != nil {
+			return err
+		}
+		return p.print(map[string]interface{}{"id": *id, "created": true})
+	case "list":
+		var resp struct {
+			Schedules []map[string]interface{} `json:"schedules"`
+		}
+		if err := c.do(http.MethodGet, "/v1/schedules", nil, nil, &resp); err != nil {
+			return err
+		}
+		return p.list(resp, resp.Schedules, []string{"id", "workflow", "cron", "paused", "nextRun", "note"})
+	case "delete", "pause", "unpause", "trigger":
+		fs := flag.NewFlagSet("schedule "+sub, flag.ContinueOnError)
+		note := fs.String("note", "", "note (pause / unpause)")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: zebractl schedule %s <scheduleId>", sub)
+		}
+		path := "/v1/schedules/" + url.PathEscape(fs.Arg(0))
+		method := http.MethodDelete
+		var body interface{}
+		if sub != "delete" {
+			path += "/" + sub
+			method = http.MethodPost
+			body = map[string]interface{}{"note": *note}
+		}
+		if err := c.do(method, path, nil, body, nil); err != nil {
+			return err
+		}
+		return p.print(map[string]interface{}{"id": fs.Arg(0), "action": sub, "ok": true})
+	default:
+		return fmt.Errorf("unknown schedule subcommand %q", sub)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultServer = "http://127.0.0.1:8888"
+
+// Profile 一组服务端连接参数
+type Profile struct {
+	Server string `json:"server"`
+	Token  string `json:"token,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// Config 保存在 $ZEBRACTL_CONFIG 或 <UserConfigDir>/zebractl/config.json
+type Config struct {
+	Current  string              `json:"current"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+func configPath() (string, error) {
+	if p := os.Getenv("ZEBRACTL_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "zebractl", "config.json"), nil
+}
+
+// loadConfig 读取配置文件，文件不存在时返回只含 default profile 的配置
+func loadConfig() (*Config, error) {
+	cfg := &Config{Current: "default", Profiles: map[string]*Profile{}}
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*Profile{}
+	}
+	return cfg, nil
+}
+
+func (c *Config) save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	// token 属于凭据，只允许当前用户读写
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// profile 返回指定（或当前）profile 的副本，缺省 server 为本地地址
+func (c *Config) profile(name string) Profile {
+	if name == "" {
+		name = c.Current
+	}
+	p := Profile{Server: defaultServer}
+	if found, ok := c.Profiles[name]; ok && found != nil {
+		if found.Server != "" {
+			p.Server = found.Server
+		}
+		p.Token = found.Token
+		p.Tenant = found.Tenant
+	}
+	return p
+}
+
+// runConfig 处理 config view / use / set
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: zebractl config view|use <profile>|set [-profile name] [-server url] [-token t] [-tenant name]")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "view":
+		masked := *cfg
+		masked.Profiles = make(map[string]*Profile, len(cfg.Profiles))
+		for n, p := range cfg.Profiles {
+			cp := *p
+			if cp.Token != "" {
+				cp.Token = "******"
+			}
+			masked.Profiles[n] = &cp
+		}
+		return (&printer{format: "json", w: os.Stdout}).print(masked)
+	case "use":
+		if len(args) != 2 {
+			return errors.New("usage: zebractl config use <profile>")
+		}
+		if _, ok := cfg.Profiles[args[1]]; !ok {
+			return fmt.Errorf("profile %q not found", args[1])
+		}
+		cfg.Current = args[1]
+		return cfg.save()
+	case "set":
+		fs := flag.NewFlagSet("config set", flag.ContinueOnError)
+		name := fs.String("profile", "", "profile to update (default: current profile)")
+		server := fs.String("server", "", "server URL")
+		token := fs.String("token", "", "auth token")
+		tenant := fs.String("tenant", "", "tenant sent as X-Tenant")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *name == "" {
+			*name = cfg.Current
+		}
+		p, ok := cfg.Profiles[*name]
+		if !ok || p == nil {
+			p = &Profile{Server: defaultServer}
+			cfg.Profiles[*name] = p
+		}
+		if *server != "" {
+			p.Server = *server
+		}
+		if *token != "" {
+			p.Token = *token
+		}
+		if *tenant != "" {
+			p.Tenant = *tenant
+		}
+		return cfg.save()
+	default:
+		return fmt.Errorf("unknown config subcommand %q", args[0])
+	}
+}
@@ -0,0 +1,131 @@
+// zebractl 是 zebra-workflow HTTP API 的命令行客户端。
+//
+//	zebractl [-profile name] [-server url] [-token t] [-tenant name] [-o table|json] <command> [args]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command 是一个子命令的实现，args 为子命令名之后的参数
+type command struct {
+	usage string
+	run   func(c *apiClient, p *printer, args []string) error
+}
+
+// commands 在 init 中赋值，避免子命令引用 commands（打印 usage）造成初始化循环
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"start":      {"start [-name DSLWorkflow] [-version v1] [-file dsl.json] [-input json] [-var k=v ...]", runStart},
+		"status":     {"status <workflowId>", runStatus},
+		"result":     {"result <workflowId>", runResult},
+		"signal":     {"signal <workflowId> <signalName> [-payload json | -payload-file f]", runSignal},
+		"cancel":     {"cancel <workflowId>", runCancel},
+		"terminate":  {"terminate [-reason text] <workflowId>", runTerminate},
+		"reset":      {"reset [-type first|last] [-event id] [-reason text] <workflowId>", runReset},
+		"list":       {"list [-query q] [-page-size n] [-page-token t]", runList},
+		"validate":   {"validate -file dsl.json", runValidate},
+		"simulate":   {"simulate -file dsl.json [-mocks mocks.json] [-var k=v ...]", runSimulate},
+		"schedule":   {"schedule create|list|delete|pause|unpause|trigger ...", runSchedule},
+		"activities": {"activities [name]", runActivities},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	global := flag.NewFlagSet("zebractl", flag.ContinueOnError)
+	profile := global.String("profile", "", "config profile to use (default: current profile)")
+	server := global.String("server", "", "server URL, overrides the profile")
+	token := global.String("token", "", "auth token, overrides the profile")
+	tenant := global.String("tenant", "", "tenant (X-Tenant header), overrides the profile")
+	output := global.String("o", "table", "output format: table or json")
+	global.Usage = usage
+	if err := global.Parse(args); err != nil {
+		return 2
+	}
+	rest := global.Args()
+	if len(rest) == 0 {
+		usage()
+		return 2
+	}
+	name, cmdArgs := rest[0], rest[1:]
+
+	if name == "config" {
+		if err := runConfig(cmdArgs); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if name == "secrets" {
+		if err := runSecrets(cmdArgs); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		return 2
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "invalid output format %q (table or json)\n", *output)
+		return 2
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	p := cfg.profile(*profile)
+	if v := os.Getenv("ZEBRACTL_SERVER"); v != "" {
+		p.Server = v
+	}
+	if v := os.Getenv("ZEBRACTL_TOKEN"); v != "" {
+		p.Token = v
+	}
+	if *server != "" {
+		p.Server = *server
+	}
+	if *token != "" {
+		p.Token = *token
+	}
+	if *tenant != "" {
+		p.Tenant = *tenant
+	}
+
+	if err := cmd.run(newAPIClient(p), &printer{format: *output, w: os.Stdout}, cmdArgs); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: zebractl [-profile name] [-server url] [-token t] [-o table|json] <command> [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintln(os.Stderr, "  "+commands[n].usage)
+	}
+	fmt.Fprintln(os.Stderr, "  config view|use <profile>|set [-profile name] [-server url] [-token t]")
+	fmt.Fprintln(os.Stderr, "  secrets seal|names -keyring keyring.json -in file [-out file]")
+	fmt.Fprintln(os.Stderr, "\nenvironment: ZEBRACTL_CONFIG, ZEBRACTL_SERVER, ZEBRACTL_TOKEN")
+}
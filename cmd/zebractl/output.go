@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer 按 -o 指定的格式输出：json 原样缩进输出，table 输出对齐的表格
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+// print 输出任意响应；table 模式下对象展开为 KEY / VALUE 两列
+func (p *printer) print(v interface{}) error {
+	if p.format == "json" {
+		return p.json(v)
+	}
+	var generic interface{}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return err
+	}
+	rows := make([][]string, 0)
+	flatten("", generic, &rows)
+	return p.table([]string{"KEY", "VALUE"}, rows)
+}
+
+// list 输出对象数组：json 模式原样输出 v，table 模式按 columns 取每个元素的字段
+func (p *printer) list(v interface{}, items []map[string]interface{}, columns []string) error {
+	if p.format == "json" {
+		return p.json(v)
+	}
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = strings.ToUpper(c)
+	}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = cell(item[c])
+		}
+		rows = append(rows, row)
+	}
+	return p.table(headers, rows)
+}
+
+func (p *printer) json(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (p *printer) table(headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, r := range rows {
+		fmt.Fprintln(tw, strings.Join(r, "\t"))
+	}
+	return tw.Flush()
+}
+
+// flatten 把嵌套对象展开为 a.b[0].c 形式的行，键按字典序
+func flatten(prefix string, v interface{}, rows *[][]string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flatten(key, t[k], rows)
+		}
+	case []interface{}:
+		for i, e := range t {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), e, rows)
+		}
+	default:
+		*rows = append(*rows, []string{prefix, cell(v)})
+	}
+}
+
+// cell 把单个值格式化为表格单元格，对象和数组压缩为一行 JSON
+func cell(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(t)
+		return string(b)
+	default:
+		return fmt.Sprint(t)
+	}
+}
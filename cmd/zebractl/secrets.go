@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"zebra-workflow/internal/codec"
+	"zebra-workflow/internal/secrets"
+)
+
+// runSecrets 在本地生成 / 检查 worker 的加密 secret 文件（secrets.provider: file），不访问服务端
+func runSecrets(args []string) error {
+	const usage = "usage: zebractl secrets seal -keyring keyring.json -in secrets.json [-out secrets.enc.json] | names -keyring keyring.json -in secrets.enc.json"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	fs := flag.NewFlagSet("secrets "+args[0], flag.ContinueOnError)
+	keyring := fs.String("keyring", "", "keyring file, same format as temporal.codec.keyringFile")
+	in := fs.String("in", "", "input file")
+	out := fs.String("out", "", "output file (default: stdout)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *keyring == "" || *in == "" {
+		return errors.New(usage)
+	}
+	ring, err := codec.LoadKeyring(*keyring)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "seal":
+		// 明文文件：{"smtp/password": "...", "api/token": "..."}
+		var values map[string]string
+		if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("%s: %w", *in, err)
+		}
+		sealed, err := secrets.Seal(ring, values)
+		if err != nil {
+			return err
+		}
+		if *out == "" {
+			_, err = fmt.Println(string(sealed))
+			return err
+		}
+		return os.WriteFile(*out, append(sealed, '\n'), 0o600)
+	case "names":
+		values, err := secrets.Open(ring, data)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(values))
+		for n := range values {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Println(n)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown secrets subcommand %q", args[0])
+	}
+}
@@ -0,0 +1,12 @@
+// Script 活动示例：input.order 为前一个活动返回的订单 JSON
+function main(input) {
+  var items = input.order.items || [];
+  if (items.length === 0) {
+    var err = new Error("order has no items");
+    err.name = "EmptyOrder";
+    err.details = { orderId: input.order.id };
+    throw err;
+  }
+  var total = items.reduce(function (sum, item) { return sum + item.price * item.quantity; }, 0);
+  return { orderId: input.order.id, total: Math.round(total * 100) / 100 };
+}
@@ -2,7 +2,6 @@ package activity
 
 import (
 	"context"
-	"fmt"
 
 	logger "zebra-workflow/internal/log"
 )
@@ -19,8 +18,9 @@ func NewActivityImpl() *ActivityImpl {
 func (a *ActivityImpl) DoSomethingActivity(ctx context.Context, input map[string]interface{}) (string, error) {
 	// 在这里实现业务逻辑（注意：activity 应保持幂等或在上层处理重试）
 	// log, metrics, error handling...
-	fmt.Println("activity received input:", input)
-	logger.Sugar.Infow("activity.DoSomethingActivity finished", "result", input)
+	log := logger.ForActivity(ctx)
+	log.Debugw("activity received input", "input", input)
+	log.Infow("activity.DoSomethingActivity finished", "result", input)
 	// 模拟工作
 	return "ok", nil
 }
@@ -0,0 +1,38 @@
+package activity
+
+import (
+	"context"
+	"encoding/base64"
+
+	"go.temporal.io/sdk/activity"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// AsyncActivity 等待外部系统（支付回调、人工审核等）完成的活动。参数与 HttpRequest 相同（均可选）：
+// url 不为空时发送通知请求，模板数据除全部参数外还有 taskToken（base64url 编码）、workflowId、runId、activityId；
+// 请求成功后活动保持运行，直到外部系统调用 POST /v1/activities/complete 完成、失败或取消它。
+// 外部系统可以调用 POST /v1/activities/heartbeat 心跳，响应中的 cancelRequested 表示活动已被取消（如 workflow 被取消）。
+// 活动重试时重新发送通知，旧的 taskToken 失效。本地活动不能异步完成。
+func AsyncActivity(ctx context.Context, input map[string]string) (interface{}, error) {
+	info := activity.GetInfo(ctx)
+	if info.IsLocalActivity {
+		return nil, invalidArgument("AsyncActivity cannot run as a local activity")
+	}
+	log := logger.ForActivity(ctx)
+	if input["url"] != "" {
+		data := make(map[string]string, len(input)+4)
+		for k, v := range input {
+			data[k] = v
+		}
+		data["taskToken"] = base64.RawURLEncoding.EncodeToString(info.TaskToken)
+		data["workflowId"] = info.WorkflowExecution.ID
+		data["runId"] = info.WorkflowExecution.RunID
+		data["activityId"] = info.ActivityID
+		if _, _, err := doHTTP(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+	log.Infow("activity waiting for external completion", "activityId", info.ActivityID, "notified", input["url"] != "")
+	return nil, activity.ErrResultPending
+}
@@ -0,0 +1,184 @@
+package activity
+
+import (
+	"encoding/json"
+
+	"zebra-workflow/internal/catalog"
+)
+
+// 内置活动登记到活动目录。参数都以字符串传入（DSL Arguments），JSON 参数在 schema 中说明格式；
+// 模板类活动的其余参数作为模板数据，因此 schema 不限制额外参数。
+
+var (
+	errInvalidArgument = catalog.ErrorType{Type: "InvalidArgument", Description: "参数缺失或格式错误"}
+	errHTTPStatus      = catalog.ErrorType{Type: "HTTPStatus", Retryable: true, Description: "非期望状态码；只有 retryableStatus 中的状态码重试"}
+	errTooLarge        = catalog.ErrorType{Type: "ResponseTooLarge", Description: "响应体超过 10MiB"}
+)
+
+// httpProperties HttpRequest、ExtractHTML（url 模式）和 AsyncActivity（通知请求）共用的参数
+const httpProperties = `
+	"method": {"type": "string", "description": "默认 GET"},
+	"url": {"type": "string", "description": "Go 模板，数据为全部参数"},
+	"headers": {"type": "string", "description": "JSON 对象，值为模板"},
+	"query": {"type": "string", "description": "JSON 对象，值为模板，追加到 url 的查询参数"},
+	"body": {"type": "string", "description": "请求体模板"},
+	"timeout": {"type": "string", "description": "单次请求超时，默认 30s"},
+	"expectedStatus": {"type": "string", "description": "期望的状态码，逗号分隔，支持 2xx 形式，默认 2xx"},
+	"retryableStatus": {"type": "string", "description": "非期望状态码中可重试的部分，默认 408,429,5xx"}`
+
+func init() {
+	catalog.Register(catalog.Activity{
+		Name:        "HttpRequest",
+		Description: "发送 HTTP 请求，url / headers / query / body 为 Go 模板",
+		Version:     "v1",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {` + httpProperties + `}, "required": ["url"]}`),
+		OutputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"status": {"type": "integer"},
+			"headers": {"type": "object", "additionalProperties": {"type": "string"}},
+			"body": {"description": "JSON 响应解析为对象，其余为文本"}}}`),
+		Errors:    []catalog.ErrorType{errInvalidArgument, errHTTPStatus, errTooLarge},
+		Heartbeat: true,
+		Func:      HttpRequest,
+	})
+	catalog.Register(catalog.Activity{
+		Name:         "AsyncActivity",
+		Description:  "可选地发送带 taskToken 的通知请求，然后等待外部系统通过 /v1/activities/complete 完成",
+		Version:      "v1",
+		InputSchema:  json.RawMessage(`{"type": "object", "properties": {` + httpProperties + `}}`),
+		OutputSchema: json.RawMessage(`{"description": "外部系统完成活动时提交的 result"}`),
+		Errors: []catalog.ErrorType{errInvalidArgument, errHTTPStatus,
+			{Type: "AsyncActivityFailed", Retryable: true, Description: "外部系统报告的失败（未指定类型时），nonRetryable 时不重试"}},
+		Heartbeat: true,
+		Func:      AsyncActivity,
+	})
+	catalog.Register(catalog.Activity{
+		Name:        "ExtractHTML",
+		Description: "按 CSS 选择器从 HTML（html 参数、请求 url 或依次请求 urls）中提取字段",
+		Version:     "v1",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"html": {"type": "string", "description": "原始 HTML，为空时请求 url 或 urls"},
+			"urls": {"type": "string", "description": "JSON 字符串数组，依次抓取多个页面，结果为 {pages: [...]}；启用 blob store 时重试跳过已完成的页面"},
+			"fields": {"type": "string", "description": "JSON 对象：输出字段 -> 选择器或 {selector, mode, attr, cardinality, required}"},` +
+			httpProperties + `}, "required": ["fields"], "anyOf": [{"required": ["html"]}, {"required": ["url"]}, {"required": ["urls"]}]}`),
+		OutputSchema: json.RawMessage(`{"type": "object", "additionalProperties": {"type": ["string", "array"]}}`),
+		Errors: []catalog.ErrorType{errInvalidArgument, errHTTPStatus, errTooLarge,
+			{Type: "SelectorNotMatched", Description: "必填字段没有匹配"}},
+		Heartbeat: true,
+		Func:      ExtractHTML,
+	})
+	catalog.Register(catalog.Activity{
+		Name:        "SampleActivity",
+		Description: "抓取 CSDN 文章页的标题、时间和正文；即使用默认 fields 的 ExtractHTML",
+		Version:     "v1",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"url": {"type": "string"},
+			"fields": {"type": "string", "description": "覆盖默认字段"}}, "required": ["url"]}`),
+		OutputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"title": {"type": "string"}, "time": {"type": "string"}, "content": {"type": "string"}}}`),
+		Errors:    []catalog.ErrorType{errInvalidArgument, errHTTPStatus, {Type: "SelectorNotMatched"}},
+		Heartbeat: true,
+		Func:      SampleActivity,
+	})
+	catalog.Register(catalog.Activity{
+		Name:         "GetTitle",
+		Description:  "从 SampleActivity 的结果 r1 中取出标题",
+		Version:      "v1",
+		InputSchema:  json.RawMessage(`{"type": "object", "properties": {"r1": {"type": "string", "description": "SampleActivity 的结果"}}, "required": ["r1"]}`),
+		OutputSchema: json.RawMessage(`{"type": "object", "properties": {"标题": {"type": "string"}}}`),
+		Func:         GetTitle,
+	})
+	catalog.Register(catalog.Activity{
+		Name:         "DoSomethingActivity",
+		Description:  "示例活动，记录输入并返回 ok",
+		Version:      "v1",
+		InputSchema:  json.RawMessage(`{"type": "object"}`),
+		OutputSchema: json.RawMessage(`{"type": "string"}`),
+		Func:         DoSomethingActivity,
+	})
+	catalog.Register(catalog.Activity{
+		Name:        "RenderTemplate",
+		Description: "渲染 Go text/template，数据为其余参数（JSON 参数会先解析）",
+		Version:     "v1",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"template": {"type": "string"},
+			"output": {"type": "string", "enum": ["text", "json"], "description": "默认 text"}}, "required": ["template"]}`),
+		OutputSchema: json.RawMessage(`{"description": "output 为 text 时是字符串，json 时是解析后的值"}`),
+		Errors:       []catalog.ErrorType{errInvalidArgument, {Type: "TransformFailed", Description: "渲染结果不是合法 JSON"}},
+		Func:         RenderTemplate,
+	})
+	catalog.Register(catalog.Activity{
+		Name:        "TransformJSON",
+		Description: "用 jq 表达式变换 JSON，其余参数作为 $变量",
+		Version:     "v1",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"expression": {"type": "string"},
+			"input": {"type": "string", "description": "输入 JSON，为空时为 null"},
+			"all": {"type": "string", "enum": ["true", "false"], "description": "true 时返回全部输出组成的数组"}}, "required": ["expression"]}`),
+		Errors: []catalog.ErrorType{errInvalidArgument, {Type: "TransformFailed", Description: "jq 执行错误"}},
+		Func:   TransformJSON,
+	})
+
+	email := catalog.Activity{
+		Name:        "SendEmail",
+		Description: "通过 smtp 配置的服务器发送邮件，同一个幂等键只发送一次",
+		Version:     "v1",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"to": {"type": "string", "description": "逗号分隔的地址列表"},
+			"cc": {"type": "string"},
+			"bcc": {"type": "string"},
+			"replyTo": {"type": "string"},
+			"from": {"type": "string", "description": "默认 smtp.from"},
+			"subject": {"type": "string", "description": "Go text/template，数据为全部参数"},
+			"text": {"type": "string", "description": "纯文本正文模板"},
+			"html": {"type": "string", "description": "HTML 正文模板（html/template）"},
+			"attachments": {"type": "string", "description": "JSON 数组 [{blob | content, filename, contentType}]"},
+			"idempotencyKey": {"type": "string", "description": "默认 workflowId/runId/activityId"}},
+			"anyOf": [{"required": ["to"]}, {"required": ["cc"]}, {"required": ["bcc"]}]}`),
+		OutputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"messageId": {"type": "string"}, "recipients": {"type": "integer"}, "duplicate": {"type": "boolean"}}}`),
+		Errors: []catalog.ErrorType{errInvalidArgument,
+			{Type: "SMTPRejected", Description: "SMTP 5xx 响应"},
+			{Type: "AttachmentNotFound", Description: "附件 blob 不存在"},
+			{Type: "EmailNotConfigured", Description: "worker 未配置 smtp.host"}},
+	}
+	catalog.Register(email)
+	// 旧的邮件示例活动名，参数 body 等同于 text
+	for _, name := range []string{"SampleActivitySendEmail", "SampleActivitySendEmailTyped"} {
+		legacy := email
+		legacy.Name = name
+		legacy.Deprecated = "使用 SendEmail"
+		catalog.Register(legacy)
+	}
+
+	catalog.Register(catalog.Activity{
+		Name:        "SQLQuery",
+		Description: "在 databases 配置的命名连接上执行参数化 SQL，:name 引用其余参数",
+		Version:     "v1",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"connection": {"type": "string"},
+			"query": {"type": "string"},
+			"statements": {"type": "string", "description": "JSON 数组 [{query, mode}]，在同一个事务中执行；与 query 二选一"},
+			"mode": {"type": "string", "enum": ["query", "exec"]},
+			"maxRows": {"type": "string", "description": "默认 1000"},
+			"timeout": {"type": "string", "description": "默认 30s"}},
+			"required": ["connection"], "anyOf": [{"required": ["query"]}, {"required": ["statements"]}]}`),
+		OutputSchema: json.RawMessage(`{"description": "query 返回行数组，exec 返回 {rowsAffected}，statements 返回每条语句结果组成的数组"}`),
+		Errors: []catalog.ErrorType{errInvalidArgument,
+			{Type: "SQLError", Description: "SQL 语法、约束或数据错误"},
+			{Type: "TooManyRows", Description: "结果超过 maxRows"}},
+	})
+	catalog.Register(catalog.Activity{
+		Name:        "Script",
+		Description: "在沙箱子进程中执行 JavaScript，其余参数作为全局对象 input",
+		Version:     "v1",
+		InputSchema: json.RawMessage(`{"type": "object", "properties": {
+			"script": {"type": "string", "description": "内联脚本"},
+			"scriptName": {"type": "string", "description": "scripts.dir 下的存储脚本名，与 script 二选一"}},
+			"anyOf": [{"required": ["script"]}, {"required": ["scriptName"]}]}`),
+		OutputSchema: json.RawMessage(`{"description": "main(input) 或最后一个表达式的值"}`),
+		Errors: []catalog.ErrorType{errInvalidArgument,
+			{Type: "ScriptTimeout", Description: "超过 scripts.timeout"},
+			{Type: "ScriptMemoryLimit", Description: "超过 scripts.maxMemory"},
+			{Type: "ScriptError", Description: "脚本抛出的错误（类型为 error.name），带 retryable: true 时重试"}},
+	})
+}
@@ -0,0 +1,580 @@
+package activity
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+
+	"zebra-workflow/internal/blobstore"
+	logger "zebra-workflow/internal/log"
+)
+
+const (
+	// maxAttachmentBytes 单封邮件附件总大小上限
+	maxAttachmentBytes = 25 << 20
+	// sentMemoryTTL 未启用 blob store 时进程内已发送标记的保留时间
+	sentMemoryTTL = 24 * time.Hour
+
+	defaultSMTPTimeout = 30 * time.Second
+)
+
+// SMTPConfig 映射 smtp
+type SMTPConfig struct {
+	Host string `json:"host,optional" yaml:"host"`
+	// Port 默认 587（security 为 tls 时 465）
+	Port     int    `json:"port,optional" yaml:"port"`
+	Username string `json:"username,optional" yaml:"username"`
+	// PasswordEnv 保存密码的环境变量名
+	PasswordEnv string `json:"passwordEnv,optional" yaml:"passwordEnv"`
+	// Security starttls（要求 STARTTLS）/ tls（465 端口的隐式 TLS）/ none；为空时服务器支持 STARTTLS 就使用
+	Security string `json:"security,optional" yaml:"security"`
+	// InsecureSkipVerify 仅用于开发环境
+	InsecureSkipVerify bool `json:"insecureSkipVerify,optional" yaml:"insecureSkipVerify"`
+	// From 默认发件人，可被活动参数 from 覆盖
+	From string `json:"from,optional" yaml:"from"`
+	// Timeout 连接和发送的超时，默认 30s
+	Timeout string `json:"timeout,optional" yaml:"timeout"`
+}
+
+// Attachment attachments 参数中的一项，内容来自 blob store（blob）或 base64 内联（content）
+type Attachment struct {
+	Blob        string `json:"blob"`
+	Content     string `json:"content"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+}
+
+// EmailSender 提供 SendEmail 活动，注册时使用指针（活动名为方法名）
+type EmailSender struct {
+	cfg      SMTPConfig
+	password string
+	timeout  time.Duration
+	// store 用于读取附件和记录已发送的幂等键，为 nil 时幂等键只在进程内记录
+	store blobstore.Store
+
+	mu   sync.Mutex
+	sent map[string]sentRecord
+}
+
+// sentRecord 已发送标记的内容
+type sentRecord struct {
+	MessageID string    `json:"messageId"`
+	SentAt    time.Time `json:"sentAt"`
+}
+
+// NewEmailSender 校验 SMTP 配置；未配置 host 时 SendEmail 返回不可重试错误
+func NewEmailSender(cfg SMTPConfig, store blobstore.Store) (*EmailSender, error) {
+	s := &EmailSender{cfg: cfg, timeout: defaultSMTPTimeout, store: store, sent: make(map[string]sentRecord)}
+	switch cfg.Security {
+	case "", "starttls", "tls", "none":
+	default:
+		return nil, fmt.Errorf("smtp: unknown security %q (starttls, tls or none)", cfg.Security)
+	}
+	if s.cfg.Port == 0 {
+		s.cfg.Port = 587
+		if cfg.Security == "tls" {
+			s.cfg.Port = 465
+		}
+	}
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("smtp: invalid timeout %q", cfg.Timeout)
+		}
+		s.timeout = d
+	}
+	if cfg.From != "" {
+		if _, err := mail.ParseAddress(cfg.From); err != nil {
+			return nil, fmt.Errorf("smtp: invalid from %q: %w", cfg.From, err)
+		}
+	}
+	if cfg.PasswordEnv != "" {
+		s.password = os.Getenv(cfg.PasswordEnv)
+		if s.password == "" {
+			return nil, fmt.Errorf("smtp: environment variable %s is empty", cfg.PasswordEnv)
+		}
+		logger.RegisterSecret(s.password)
+	}
+	return s, nil
+}
+
+// SendEmail 通过 SMTP 发送邮件。参数（DSL Arguments，可用 "param=binding" 指定参数名）：
+//
+//	to / cc / bcc    逗号分隔的地址列表，to 和 cc / bcc 至少一个
+//	from / replyTo   默认使用 smtp.from
+//	subject          Go text/template，以全部参数为数据
+//	text             纯文本正文模板（旧示例活动的 body 参数等同于 text）
+//	html             HTML 正文模板（html/template，绑定值会被转义）
+//	attachments      JSON 数组：[{"blob": "<blob key>", "filename": "a.pdf", "contentType": "application/pdf"}]，
+//	                 也可以用 "content" 传 base64 内容
+//	idempotencyKey   幂等键，默认为 workflowId/runId/activityId，同一个键只发送一次
+//
+// 返回 {"messageId": "...", "recipients": n, "duplicate": false}；幂等键已发送过时不再发送，duplicate 为 true。
+// SMTP 5xx 响应（地址被拒、认证失败等）返回类型为 SMTPRejected 的不可重试错误，网络错误和 4xx 按 RetryPolicy 重试。
+func (s *EmailSender) SendEmail(ctx context.Context, input map[string]string) (map[string]interface{}, error) {
+	if s.cfg.Host == "" {
+		return nil, temporal.NewNonRetryableApplicationError("smtp.host is not configured", "EmailNotConfigured", nil)
+	}
+	msg, err := s.buildMessage(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	key := input["idempotencyKey"]
+	if key == "" {
+		info := activity.GetInfo(ctx)
+		key = info.WorkflowExecution.ID + "/" + info.WorkflowExecution.RunID + "/" + info.ActivityID
+	}
+	sum := sha256.Sum256([]byte(key))
+	keyHash := hex.EncodeToString(sum[:])
+	if rec, ok, err := s.lookupSent(ctx, keyHash); err != nil {
+		return nil, err
+	} else if ok {
+		logger.ForActivity(ctx).Infow("email already sent, skipping", "messageId", rec.MessageID, "sentAt", rec.SentAt)
+		return map[string]interface{}{"messageId": rec.MessageID, "recipients": len(msg.rcpt), "duplicate": true}, nil
+	}
+
+	// Message-ID 由幂等键决定，便于在收件端和日志中关联同一封邮件
+	msg.id = "<" + keyHash[:32] + "@" + domainOf(msg.from) + ">"
+	data, err := msg.render()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	if err := s.send(ctx, msg.from, msg.rcpt, data); err != nil {
+		return nil, smtpError(err)
+	}
+	logger.ForActivity(ctx).Infow("email sent", "messageId", msg.id, "recipients", len(msg.rcpt),
+		"bytes", len(data), "duration", time.Since(start).String())
+
+	if err := s.markSent(ctx, keyHash, sentRecord{MessageID: msg.id, SentAt: time.Now()}); err != nil {
+		// 邮件已经发出，返回错误会导致重试重复发送
+		logger.ForActivity(ctx).Warnw("failed to record sent email", "messageId", msg.id, "err", err)
+	}
+	return map[string]interface{}{"messageId": msg.id, "recipients": len(msg.rcpt), "duplicate": false}, nil
+}
+
+// message 渲染好的邮件
+type message struct {
+	from        string
+	header      textproto.MIMEHeader
+	rcpt        []string
+	id          string
+	text, html  string
+	attachments []attachmentData
+}
+
+type attachmentData struct {
+	filename    string
+	contentType string
+	data        []byte
+}
+
+// buildMessage 解析地址、渲染模板并读取附件
+func (s *EmailSender) buildMessage(ctx context.Context, input map[string]string) (*message, error) {
+	from := input["from"]
+	if from == "" {
+		from = s.cfg.From
+	}
+	if from == "" {
+		return nil, invalidArgument("from is required (argument or smtp.from)")
+	}
+	fromAddr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, invalidArgument("invalid from %q: %v", from, err)
+	}
+
+	msg := &message{from: fromAddr.Address, header: textproto.MIMEHeader{}}
+	msg.header.Set("From", fromAddr.String())
+	for _, field := range []string{"to", "cc", "bcc", "replyTo"} {
+		if strings.TrimSpace(input[field]) == "" {
+			continue
+		}
+		list, err := mail.ParseAddressList(input[field])
+		if err != nil {
+			return nil, invalidArgument("invalid %s %q: %v", field, input[field], err)
+		}
+		formatted := make([]string, len(list))
+		for i, a := range list {
+			formatted[i] = a.String()
+			if field != "replyTo" {
+				msg.rcpt = append(msg.rcpt, a.Address)
+			}
+		}
+		switch field {
+		case "to":
+			msg.header.Set("To", strings.Join(formatted, ", "))
+		case "cc":
+			msg.header.Set("Cc", strings.Join(formatted, ", "))
+		case "replyTo":
+			msg.header.Set("Reply-To", strings.Join(formatted, ", "))
+		}
+	}
+	if len(msg.rcpt) == 0 {
+		return nil, invalidArgument("at least one of to, cc, bcc is required")
+	}
+
+	subject, err := renderTemplate("subject", input["subject"], input)
+	if err != nil {
+		return nil, err
+	}
+	msg.header.Set("Subject", mime.QEncoding.Encode("utf-8", subject))
+
+	text := input["text"]
+	if text == "" {
+		text = input["body"]
+	}
+	if msg.text, err = renderTemplate("text", text, input); err != nil {
+		return nil, err
+	}
+	if msg.html, err = renderHTMLTemplate("html", input["html"], input); err != nil {
+		return nil, err
+	}
+	if msg.text == "" && msg.html == "" {
+		return nil, invalidArgument("text or html is required")
+	}
+
+	if input["attachments"] != "" {
+		if msg.attachments, err = s.loadAttachments(ctx, input["attachments"]); err != nil {
+			return nil, err
+		}
+	}
+	return msg, nil
+}
+
+// renderHTMLTemplate 与 renderTemplate 相同，但使用 html/template 按上下文转义绑定值
+func renderHTMLTemplate(name, text string, data map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	t, err := htmltemplate.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", invalidArgument("%s template: %v", name, err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", invalidArgument("%s template: %v", name, err)
+	}
+	return sb.String(), nil
+}
+
+// loadAttachments 解析 attachments 参数并读取内容
+func (s *EmailSender) loadAttachments(ctx context.Context, raw string) ([]attachmentData, error) {
+	var specs []Attachment
+	if err := json.Unmarshal([]byte(raw), &specs); err != nil {
+		return nil, invalidArgument("attachments must be a JSON array: %v", err)
+	}
+	var total int
+	out := make([]attachmentData, 0, len(specs))
+	for i, a := range specs {
+		if a.Filename == "" {
+			return nil, invalidArgument("attachments[%d]: filename is required", i)
+		}
+		var data []byte
+		switch {
+		case a.Blob != "" && a.Content != "":
+			return nil, invalidArgument("attachments[%d]: blob and content are mutually exclusive", i)
+		case a.Blob != "":
+			if s.store == nil {
+				return nil, invalidArgument("attachments[%d]: blob store is not enabled", i)
+			}
+			b, err := s.store.Get(ctx, a.Blob)
+			if errors.Is(err, blobstore.ErrNotFound) {
+				return nil, temporal.NewNonRetryableApplicationError(
+					fmt.Sprintf("attachments[%d]: blob %q not found", i, a.Blob), "AttachmentNotFound", err)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("attachments[%d]: read blob %q: %w", i, a.Blob, err)
+			}
+			data = b
+		case a.Content != "":
+			b, err := base64.StdEncoding.DecodeString(a.Content)
+			if err != nil {
+				return nil, invalidArgument("attachments[%d]: content is not base64: %v", i, err)
+			}
+			data = b
+		default:
+			return nil, invalidArgument("attachments[%d]: blob or content is required", i)
+		}
+		total += len(data)
+		if total > maxAttachmentBytes {
+			return nil, invalidArgument("attachments exceed %d bytes", maxAttachmentBytes)
+		}
+		ct := a.ContentType
+		if ct == "" {
+			if ct = mime.TypeByExtension(path.Ext(a.Filename)); ct == "" {
+				ct = "application/octet-stream"
+			}
+		}
+		out = append(out, attachmentData{filename: a.Filename, contentType: ct, data: data})
+	}
+	return out, nil
+}
+
+// render 生成 RFC 5322 邮件：multipart/mixed（有附件时）包含 multipart/alternative（同时有纯文本和 HTML 时）
+func (m *message) render() ([]byte, error) {
+	var buf bytes.Buffer
+	m.header.Set("Message-ID", m.id)
+	m.header.Set("Date", time.Now().Format(time.RFC1123Z))
+	m.header.Set("MIME-Version", "1.0")
+
+	var bodyHeader textproto.MIMEHeader
+	var body []byte
+	var err error
+	switch {
+	case m.text != "" && m.html != "":
+		bodyHeader, body, err = alternativePart(m.text, m.html)
+		if err != nil {
+			return nil, err
+		}
+	case m.html != "":
+		bodyHeader, body = textPart("text/html", m.html)
+	default:
+		bodyHeader, body = textPart("text/plain", m.text)
+	}
+
+	if len(m.attachments) == 0 {
+		writeHeader(&buf, m.header, bodyHeader)
+		buf.Write(body)
+		return buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	w, err := mw.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	w.Write(body)
+	for _, a := range m.attachments {
+		h := textproto.MIMEHeader{}
+		mediaType, params, err := mime.ParseMediaType(a.contentType)
+		if err != nil {
+			mediaType, params = "application/octet-stream", map[string]string{}
+		}
+		params["name"] = a.filename
+		h.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.filename}))
+		h.Set("Content-Transfer-Encoding", "base64")
+		w, err := mw.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		writeBase64Lines(w, a.data)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	writeHeader(&buf, m.header, textproto.MIMEHeader{
+		"Content-Type": {"multipart/mixed; boundary=" + strconv.Quote(mw.Boundary())},
+	})
+	buf.Write(parts.Bytes())
+	return buf.Bytes(), nil
+}
+
+// textPart 以 quoted-printable 编码的 UTF-8 文本部分
+func textPart(contentType, content string) (textproto.MIMEHeader, []byte) {
+	var b bytes.Buffer
+	qp := quotedprintable.NewWriter(&b)
+	qp.Write([]byte(content))
+	qp.Close()
+	return textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}, b.Bytes()
+}
+
+func alternativePart(text, html string) (textproto.MIMEHeader, []byte, error) {
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	for _, p := range [][2]string{{"text/plain", text}, {"text/html", html}} {
+		h, body := textPart(p[0], p[1])
+		w, err := mw.CreatePart(h)
+		if err != nil {
+			return nil, nil, err
+		}
+		w.Write(body)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + strconv.Quote(mw.Boundary())},
+	}, b.Bytes(), nil
+}
+
+// writeHeader 按字段名排序写入头部，结束后写空行
+func writeHeader(buf *bytes.Buffer, headers ...textproto.MIMEHeader) {
+	for _, h := range headers {
+		keys := make([]string, 0, len(h))
+		for k := range h {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, v := range h[k] {
+				fmt.Fprintf(buf, "%s: %s\r\n", k, v)
+			}
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+// writeBase64Lines 按 RFC 2045 每行 76 个字符写入 base64
+func writeBase64Lines(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
+
+// send 连接 SMTP 服务器并投递，连接受 ctx 和 smtp.timeout 限制
+func (s *EmailSender) send(ctx context.Context, from string, rcpt []string, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: s.cfg.Host, InsecureSkipVerify: s.cfg.InsecureSkipVerify}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("smtp: dial %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if s.cfg.Security == "tls" {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %s: %w", addr, err)
+	}
+	defer c.Close()
+
+	if s.cfg.Security == "" || s.cfg.Security == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("smtp: starttls: %w", err)
+			}
+		} else if s.cfg.Security == "starttls" {
+			return temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("smtp: %s does not support STARTTLS", addr), "SMTPRejected", nil)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("smtp: MAIL FROM: %w", err)
+	}
+	for _, r := range rcpt {
+		if err := c.Rcpt(r); err != nil {
+			return fmt.Errorf("smtp: RCPT TO %s: %w", r, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: DATA: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("smtp: write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: DATA: %w", err)
+	}
+	// DATA 已被接受即视为发出；QUIT 失败只记录日志，返回错误会导致重试重复发送
+	if err := c.Quit(); err != nil {
+		logger.ForActivity(ctx).Warnw("smtp QUIT failed after message was accepted", "err", err)
+	}
+	return nil
+}
+
+// smtpError 5xx 为永久错误，重试不会成功
+func smtpError(err error) error {
+	var app *temporal.ApplicationError
+	if errors.As(err, &app) {
+		return err
+	}
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return temporal.NewNonRetryableApplicationError(err.Error(), "SMTPRejected", nil)
+	}
+	return err
+}
+
+// lookupSent 查询幂等键是否已发送
+func (s *EmailSender) lookupSent(ctx context.Context, keyHash string) (sentRecord, bool, error) {
+	if s.store == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		rec, ok := s.sent[keyHash]
+		return rec, ok, nil
+	}
+	data, err := s.store.Get(ctx, blobstore.SentMarkerPrefix+keyHash)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return sentRecord{}, false, nil
+	}
+	if err != nil {
+		return sentRecord{}, false, fmt.Errorf("read sent marker: %w", err)
+	}
+	var rec sentRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return sentRecord{}, false, fmt.Errorf("parse sent marker: %w", err)
+	}
+	return rec, true, nil
+}
+
+// markSent 记录幂等键已发送；进程内记录会清理超过 sentMemoryTTL 的条目
+func (s *EmailSender) markSent(ctx context.Context, keyHash string, rec sentRecord) error {
+	if s.store == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for k, r := range s.sent {
+			if time.Since(r.SentAt) > sentMemoryTTL {
+				delete(s.sent, k)
+			}
+		}
+		s.sent[keyHash] = rec
+		return nil
+	}
+	data, _ := json.Marshal(rec)
+	return s.store.Put(ctx, blobstore.SentMarkerPrefix+keyHash, data)
+}
+
+func domainOf(addr string) string {
+	if i := strings.LastIndexByte(addr, '@'); i >= 0 {
+		return addr[i+1:]
+	}
+	return "localhost"
+}
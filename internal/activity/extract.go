@@ -0,0 +1,258 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"go.temporal.io/sdk/temporal"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// FieldSpec ExtractHTML 中一个输出字段的提取规则。JSON 中可以只写选择器字符串（text、single、required）
+type FieldSpec struct {
+	Selector string `json:"selector"`
+	// Mode text（默认，去掉首尾空白的文本）、attr（Attr 指定的属性）或 html（内部 HTML）
+	Mode string `json:"mode,omitempty"`
+	Attr string `json:"attr,omitempty"`
+	// Cardinality single（默认，第一个匹配，结果为字符串）或 all（全部匹配，结果为数组）
+	Cardinality string `json:"cardinality,omitempty"`
+	// Required 默认 true：没有匹配时活动失败；false 时返回空字符串 / 空数组
+	Required *bool `json:"required,omitempty"`
+}
+
+func (f *FieldSpec) UnmarshalJSON(data []byte) error {
+	var selector string
+	if err := json.Unmarshal(data, &selector); err == nil {
+		*f = FieldSpec{Selector: selector}
+		return nil
+	}
+	type plain FieldSpec
+	return json.Unmarshal(data, (*plain)(f))
+}
+
+func (f FieldSpec) required() bool {
+	return f.Required == nil || *f.Required
+}
+
+// ExtractHTML 按 CSS 选择器从 HTML 中提取字段。参数：
+//
+//	html    原始 HTML；为空时从 url 获取（url / headers / query / timeout / expectedStatus 等与 HttpRequest 相同）
+//	urls    JSON 字符串数组，依次抓取多个页面（每项与 url 相同，为模板），与 html / url 三选一
+//	fields  JSON 对象，输出字段名 -> 选择器字符串或 FieldSpec，如
+//	        {"title": "h1.title", "links": {"selector": "a", "mode": "attr", "attr": "href", "cardinality": "all"}}
+//
+// 返回字段名 -> 字符串（single）或字符串数组（all）；urls 模式返回 {"pages": [...]}，按 urls 的顺序排列每个页面的结果。
+// 必填字段没有匹配时返回不可重试的 SelectorNotMatched 错误，错误信息列出所有未匹配的字段和选择器。
+// 抓取期间定期心跳；urls 模式每完成一个页面上报下一个页面的序号，页面结果保存在 progress store 中，
+// 重试时读回已完成页面的结果，读不到的页面重新抓取。
+func ExtractHTML(ctx context.Context, input map[string]string) (map[string]interface{}, error) {
+	fields, err := parseFields(input["fields"])
+	if err != nil {
+		return nil, err
+	}
+	if input["urls"] != "" {
+		return extractPages(ctx, input, fields)
+	}
+
+	source := input["html"]
+	if source == "" {
+		if input["url"] == "" {
+			return nil, invalidArgument("one of html, url or urls is required")
+		}
+		hb := StartHeartbeat(ctx)
+		_, body, err := doHTTP(ctx, input)
+		hb.Stop()
+		if err != nil {
+			return nil, err
+		}
+		source = string(body)
+	}
+	result, err := extractFields(source, fields)
+	if err != nil {
+		return nil, err
+	}
+	logger.ForActivity(ctx).Infow("html extracted", "fields", len(fields))
+	return result, nil
+}
+
+// extractProgress urls 模式上报的心跳进度：下一个要抓取的页面序号。页面结果不放在心跳详情中（大小受限），
+// 而是以 page-<i> 保存在 progress store
+type extractProgress struct {
+	Next int `json:"next"`
+}
+
+// extractPages 依次抓取 urls 中的页面并提取字段，每完成一个页面记录一次心跳进度
+func extractPages(ctx context.Context, input map[string]string, fields map[string]FieldSpec) (map[string]interface{}, error) {
+	var urls []string
+	if err := json.Unmarshal([]byte(input["urls"]), &urls); err != nil || len(urls) == 0 {
+		return nil, invalidArgument("urls must be a non-empty JSON array of strings")
+	}
+	log := logger.ForActivity(ctx)
+	var progress extractProgress
+	if !Resume(ctx, &progress) || progress.Next < 0 || progress.Next > len(urls) {
+		progress = extractProgress{}
+	}
+
+	pages := make([]map[string]interface{}, len(urls))
+	loaded := 0
+	for i := 0; i < progress.Next; i++ {
+		if LoadProgress(ctx, pagePart(i), &pages[i]) {
+			loaded++
+		}
+	}
+	if progress.Next > 0 {
+		log.Infow("resume html extraction", "completed", progress.Next, "loaded", loaded, "total", len(urls))
+	}
+
+	hb := StartHeartbeat(ctx)
+	defer hb.Stop()
+	page := make(map[string]string, len(input))
+	for k, v := range input {
+		page[k] = v
+	}
+	for i := range urls {
+		if pages[i] != nil {
+			continue
+		}
+		page["url"] = urls[i]
+		_, body, err := doHTTP(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		result, err := extractFields(string(body), fields)
+		if err != nil {
+			log.Warnw("html extraction failed", "page", i, "url", urls[i], "err", err)
+			return nil, err
+		}
+		pages[i] = result
+		if SaveProgress(ctx, pagePart(i), result) && i >= progress.Next {
+			progress.Next = i + 1
+			hb.Record(progress)
+		}
+	}
+	log.Infow("html extracted", "fields", len(fields), "pages", len(urls))
+	return map[string]interface{}{"pages": pages}, nil
+}
+
+func pagePart(i int) string {
+	return fmt.Sprintf("page-%d", i)
+}
+
+// extractFields 解析 HTML 并按 fields 提取全部字段
+func extractFields(source string, fields map[string]FieldSpec) (map[string]interface{}, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(source))
+	if err != nil {
+		return nil, invalidArgument("parse html: %v", err)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make(map[string]interface{}, len(fields))
+	var missing []string
+	for _, name := range names {
+		spec := fields[name]
+		values, err := extractField(doc, spec)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 && spec.required() {
+			missing = append(missing, fmt.Sprintf("%s (%s)", name, spec.Selector))
+		}
+		if spec.Cardinality == "all" {
+			result[name] = values
+		} else if len(values) > 0 {
+			result[name] = values[0]
+		} else {
+			result[name] = ""
+		}
+	}
+	if len(missing) > 0 {
+		return nil, temporal.NewNonRetryableApplicationError(
+			"required selectors matched nothing: "+strings.Join(missing, ", "), "SelectorNotMatched", nil)
+	}
+	return result, nil
+}
+
+// parseFields 解析并校验 fields 参数，选择器语法错误在提取前报告
+func parseFields(raw string) (map[string]FieldSpec, error) {
+	if raw == "" {
+		return nil, invalidArgument("fields is required")
+	}
+	var fields map[string]FieldSpec
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return nil, invalidArgument("fields must be a JSON object of selectors: %v", err)
+	}
+	if len(fields) == 0 {
+		return nil, invalidArgument("fields is empty")
+	}
+	var problems []string
+	for name, f := range fields {
+		if _, err := cascadia.Compile(f.Selector); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid selector %q: %v", name, f.Selector, err))
+		}
+		switch f.Mode {
+		case "", "text", "html":
+		case "attr":
+			if f.Attr == "" {
+				problems = append(problems, name+": attr mode requires attr")
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s: unknown mode %q", name, f.Mode))
+		}
+		if f.Cardinality != "" && f.Cardinality != "single" && f.Cardinality != "all" {
+			problems = append(problems, fmt.Sprintf("%s: unknown cardinality %q", name, f.Cardinality))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, invalidArgument("%s", strings.Join(problems, "; "))
+	}
+	return fields, nil
+}
+
+// extractField 返回字段的所有取值；single 只取第一个有值的匹配
+func extractField(doc *goquery.Document, spec FieldSpec) ([]string, error) {
+	matcher, err := cascadia.Compile(spec.Selector)
+	if err != nil {
+		return nil, invalidArgument("invalid selector %q: %v", spec.Selector, err)
+	}
+	var values []string
+	var extractErr error
+	doc.FindMatcher(matcher).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var v string
+		switch spec.Mode {
+		case "attr":
+			attr, ok := s.Attr(spec.Attr)
+			if !ok {
+				return true
+			}
+			v = attr
+		case "html":
+			h, err := s.Html()
+			if err != nil {
+				extractErr = errors.New("render html: " + err.Error())
+				return false
+			}
+			v = strings.TrimSpace(h)
+		default:
+			v = strings.TrimSpace(s.Text())
+		}
+		values = append(values, v)
+		return spec.Cardinality == "all"
+	})
+	if extractErr != nil {
+		return nil, extractErr
+	}
+	return values, nil
+}
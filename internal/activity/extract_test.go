@@ -0,0 +1,81 @@
+package activity
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"go.temporal.io/sdk/testsuite"
+
+	"zebra-workflow/internal/blobstore"
+	logger "zebra-workflow/internal/log"
+)
+
+func TestMain(m *testing.M) {
+	if err := logger.Init("error", "json", []string{"stderr"}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestExtractPagesResumesFromProgressStore(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprintf(w, "<h1>%s</h1>", r.URL.Path)
+	}))
+	defer srv.Close()
+	store, err := blobstore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetProgressStore(store)
+	defer SetProgressStore(nil)
+
+	input := map[string]string{
+		"urls":   fmt.Sprintf(`["%[1]s/a", "%[1]s/b", "%[1]s/c"]`, srv.URL),
+		"fields": `{"title": "h1"}`,
+	}
+	want := map[string]interface{}{"pages": []interface{}{
+		map[string]interface{}{"title": "/a"},
+		map[string]interface{}{"title": "/b"},
+		map[string]interface{}{"title": "/c"},
+	}}
+	run := func(progress *extractProgress) map[string]interface{} {
+		t.Helper()
+		var s testsuite.WorkflowTestSuite
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(ExtractHTML)
+		if progress != nil {
+			env.SetHeartbeatDetails(*progress)
+		}
+		v, err := env.ExecuteActivity(ExtractHTML, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out map[string]interface{}
+		if err := v.Get(&out); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	if got := run(nil); !reflect.DeepEqual(got, want) || requests.Load() != 3 {
+		t.Fatalf("first attempt: %v after %d requests", got, requests.Load())
+	}
+	// 重试：前两个页面从 progress store 读回，只抓取第三个
+	requests.Store(0)
+	if got := run(&extractProgress{Next: 2}); !reflect.DeepEqual(got, want) || requests.Load() != 1 {
+		t.Fatalf("resumed attempt: %v after %d requests", got, requests.Load())
+	}
+	// 读不到的页面重新抓取
+	SetProgressStore(nil)
+	requests.Store(0)
+	if got := run(&extractProgress{Next: 2}); !reflect.DeepEqual(got, want) || requests.Load() != 3 {
+		t.Fatalf("attempt without store: %v after %d requests", got, requests.Load())
+	}
+}
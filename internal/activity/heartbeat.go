@@ -0,0 +1,164 @@
+package activity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"zebra-workflow/internal/blobstore"
+	logger "zebra-workflow/internal/log"
+)
+
+// defaultHeartbeatInterval 活动没有设置 HeartbeatTimeout 时的心跳间隔；心跳同时用于接收取消请求
+const defaultHeartbeatInterval = 10 * time.Second
+
+// Heartbeat 长时间运行的活动的心跳：后台定期上报最近一次 Record 的进度，直到 Stop。
+// 心跳间隔为活动 HeartbeatTimeout 的一半（未设置时 10s）；活动被取消时 ctx 随之取消。
+// 本地活动不支持心跳，此时 Record / Stop 只记录进度，不上报。
+type Heartbeat struct {
+	ctx  context.Context
+	mu   sync.Mutex
+	data interface{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartHeartbeat 开始心跳。初始进度为上次尝试最后上报的进度，避免重试的活动在第一次 Record 前把它清空
+func StartHeartbeat(ctx context.Context) *Heartbeat {
+	h := &Heartbeat{ctx: ctx, stop: make(chan struct{}), done: make(chan struct{})}
+	if !activity.IsActivity(ctx) || activity.GetInfo(ctx).IsLocalActivity {
+		close(h.done)
+		return h
+	}
+	var previous json.RawMessage
+	if activity.HasHeartbeatDetails(ctx) && activity.GetHeartbeatDetails(ctx, &previous) == nil {
+		h.data = previous
+	}
+	interval := defaultHeartbeatInterval
+	if t := activity.GetInfo(ctx).HeartbeatTimeout; t > 0 {
+		interval = max(t/2, time.Millisecond)
+	}
+	go h.loop(interval)
+	return h
+}
+
+func (h *Heartbeat) loop(interval time.Duration) {
+	defer close(h.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.send()
+		case <-h.ctx.Done():
+			return
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// Record 记录进度并立即上报（SDK 会按心跳超时合并过于频繁的上报）。details 需可 JSON 序列化，
+// 活动重试时由 Resume 取回
+func (h *Heartbeat) Record(details interface{}) {
+	h.mu.Lock()
+	h.data = details
+	h.mu.Unlock()
+	h.send()
+}
+
+func (h *Heartbeat) send() {
+	select {
+	case <-h.done:
+		return
+	default:
+	}
+	h.mu.Lock()
+	data := h.data
+	h.mu.Unlock()
+	activity.RecordHeartbeat(h.ctx, data)
+}
+
+// Stop 停止后台心跳，可以多次调用
+func (h *Heartbeat) Stop() {
+	select {
+	case <-h.stop:
+	default:
+		close(h.stop)
+	}
+	<-h.done
+}
+
+// Resume 把上次尝试最后上报的进度解析到 v（指针）。首次执行、本地活动或进度无法解析时返回 false，活动应从头开始
+func Resume(ctx context.Context, v interface{}) bool {
+	if !activity.IsActivity(ctx) || !activity.HasHeartbeatDetails(ctx) {
+		return false
+	}
+	if err := activity.GetHeartbeatDetails(ctx, v); err != nil {
+		logger.ForActivity(ctx).Warnw("ignore heartbeat details from previous attempt", "err", err)
+		return false
+	}
+	return true
+}
+
+// progressStore 保存活动中间结果的 blob store，为 nil 时重试的活动需要重新计算这些结果
+var progressStore blobstore.Store
+
+// SetProgressStore 设置保存活动中间结果的 blob store，worker 启动时调用。
+// 心跳详情只应包含进度位置，较大的中间结果用 SaveProgress 保存，重试时用 LoadProgress 读回
+func SetProgressStore(s blobstore.Store) {
+	progressStore = s
+}
+
+// progressKey 当前活动（同一活动的各次尝试相同）名为 part 的中间结果的 key
+func progressKey(ctx context.Context, part string) (string, bool) {
+	if progressStore == nil || !activity.IsActivity(ctx) {
+		return "", false
+	}
+	info := activity.GetInfo(ctx)
+	sum := sha256.Sum256([]byte(info.WorkflowExecution.ID + "/" + info.WorkflowExecution.RunID + "/" + info.ActivityID))
+	return fmt.Sprintf("%s%s/%s", blobstore.ProgressPrefix, hex.EncodeToString(sum[:]), part), true
+}
+
+// SaveProgress 保存中间结果 v（JSON），没有 progress store 时返回 false
+func SaveProgress(ctx context.Context, part string, v interface{}) bool {
+	key, ok := progressKey(ctx, part)
+	if !ok {
+		return false
+	}
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = progressStore.Put(ctx, key, data)
+	}
+	if err != nil {
+		logger.ForActivity(ctx).Warnw("save activity progress failed", "part", part, "err", err)
+		return false
+	}
+	return true
+}
+
+// LoadProgress 读回之前尝试保存的中间结果到 v（指针），不存在或无法读取时返回 false
+func LoadProgress(ctx context.Context, part string, v interface{}) bool {
+	key, ok := progressKey(ctx, part)
+	if !ok {
+		return false
+	}
+	data, err := progressStore.Get(ctx, key)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		if !errors.Is(err, blobstore.ErrNotFound) {
+			logger.ForActivity(ctx).Warnw("load activity progress failed", "part", part, "err", err)
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,295 @@
+package activity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+
+	logger "zebra-workflow/internal/log"
+)
+
+const (
+	// maxResponseBody 响应体上限，超过时返回不可重试错误（大结果会由 blob store 转存，但不应无限读取）
+	maxResponseBody = 10 << 20
+	// maxErrorBody 错误信息中附带的响应体长度
+	maxErrorBody = 512
+
+	defaultHTTPTimeout     = 30 * time.Second
+	defaultExpectedStatus  = "2xx"
+	defaultRetryableStatus = "408,429,5xx"
+)
+
+// httpClient 所有 HttpRequest 共享连接池，超时由每个请求的 context 控制
+var httpClient = &http.Client{}
+
+// HttpRequest 通用 HTTP 请求活动。参数（DSL Arguments，可用 "param=binding" 指定参数名）：
+//
+//	method           默认 GET
+//	url              Go text/template，以全部参数为数据，如 https://api.example.com/users/{{.userId}}
+//	headers          JSON 对象，值为模板，如 {"Authorization": "Bearer {{.apiToken}}"}
+//	query            JSON 对象，值为模板，追加到 url 的查询参数
+//	body             请求体模板；JSON 请求需在 headers 中设置 Content-Type
+//	timeout          单次请求超时，默认 30s（同时受活动 StartToCloseTimeout 限制）
+//	expectedStatus   期望的状态码，逗号分隔，支持 2xx 形式，默认 2xx
+//	retryableStatus  非期望状态码中可重试的部分，默认 408,429,5xx，其余返回不可重试错误
+//
+// 返回 {"status": 200, "headers": {...}, "body": <JSON 或文本>}。
+// 非期望状态码返回类型为 HTTPStatus 的 ApplicationError，429 / 503 带 Retry-After 时按其设置下次重试间隔。
+func HttpRequest(ctx context.Context, input map[string]string) (map[string]interface{}, error) {
+	// 慢请求 / 大响应期间保持心跳，活动设置 HeartbeatTimeout 时可以较快发现 worker 崩溃，取消也能及时中断请求
+	hb := StartHeartbeat(ctx)
+	defer hb.Stop()
+	resp, body, err := doHTTP(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]interface{}, len(resp.Header))
+	for k, v := range resp.Header {
+		headers[k] = strings.Join(v, ", ")
+	}
+	return map[string]interface{}{
+		"status":  resp.StatusCode,
+		"headers": headers,
+		"body":    parseBody(resp.Header.Get("Content-Type"), body),
+	}, nil
+}
+
+// doHTTP 按 HttpRequest 的参数发送请求并读取响应体，状态码不符合 expectedStatus 时返回对应的错误
+func doHTTP(ctx context.Context, input map[string]string) (*http.Response, []byte, error) {
+	req, timeout, err := buildHTTPRequest(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	expected, err := parseStatusRules("expectedStatus", input["expectedStatus"], defaultExpectedStatus)
+	if err != nil {
+		return nil, nil, err
+	}
+	retryable, err := parseStatusRules("retryableStatus", input["retryableStatus"], defaultRetryableStatus)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	resp, err := httpClient.Do(req.WithContext(reqCtx))
+	if err != nil {
+		// 网络错误、超时：按活动的 RetryPolicy 重试
+		return nil, nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: read body: %w", req.Method, req.URL.Redacted(), err)
+	}
+	logger.ForActivity(ctx).Infow("http request finished", "method", req.Method, "url", req.URL.Redacted(),
+		"status", resp.StatusCode, "bytes", len(body), "duration", time.Since(start).String())
+	if len(body) > maxResponseBody {
+		return nil, nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("%s %s: response body exceeds %d bytes", req.Method, req.URL.Redacted(), maxResponseBody), "ResponseTooLarge", nil)
+	}
+	if !expected(resp.StatusCode) {
+		return nil, nil, statusError(req, resp, body, retryable(resp.StatusCode))
+	}
+	return resp, body, nil
+}
+
+// buildHTTPRequest 渲染 url / headers / query / body 模板并构造请求
+func buildHTTPRequest(input map[string]string) (*http.Request, time.Duration, error) {
+	method := strings.ToUpper(input["method"])
+	if method == "" {
+		method = http.MethodGet
+	}
+	if input["url"] == "" {
+		return nil, 0, invalidArgument("url is required")
+	}
+	rawURL, err := renderTemplate("url", input["url"], input)
+	if err != nil {
+		return nil, 0, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, 0, invalidArgument("url %q is not an absolute http(s) URL", rawURL)
+	}
+
+	if input["query"] != "" {
+		query, err := renderJSONObject("query", input["query"], input)
+		if err != nil {
+			return nil, 0, err
+		}
+		q := u.Query()
+		for k, v := range query {
+			q.Add(k, v)
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	var body io.Reader
+	if input["body"] != "" {
+		b, err := renderTemplate("body", input["body"], input)
+		if err != nil {
+			return nil, 0, err
+		}
+		body = strings.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, 0, invalidArgument("%v", err)
+	}
+
+	if input["headers"] != "" {
+		headers, err := renderJSONObject("headers", input["headers"], input)
+		if err != nil {
+			return nil, 0, err
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+	}
+
+	timeout := defaultHTTPTimeout
+	if input["timeout"] != "" {
+		if timeout, err = time.ParseDuration(input["timeout"]); err != nil || timeout <= 0 {
+			return nil, 0, invalidArgument("invalid timeout %q", input["timeout"])
+		}
+	}
+	return req, timeout, nil
+}
+
+// renderTemplate 以全部参数为数据渲染模板，引用不存在的参数时报错
+func renderTemplate(name, text string, data map[string]string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", invalidArgument("%s template: %v", name, err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", invalidArgument("%s template: %v", name, err)
+	}
+	return sb.String(), nil
+}
+
+// renderJSONObject 解析 JSON 对象参数（headers / query）并渲染每个值
+func renderJSONObject(name, raw string, data map[string]string) (map[string]string, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &obj); err != nil {
+		return nil, invalidArgument("%s must be a JSON object: %v", name, err)
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		s, ok := v.(string)
+		if !ok {
+			b, _ := json.Marshal(v)
+			s = string(b)
+		}
+		r, err := renderTemplate(name+"."+k, s, data)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = r
+	}
+	return out, nil
+}
+
+// parseStatusRules 解析 "200,201,2xx" 形式的状态码规则
+func parseStatusRules(name, spec, def string) (func(int) bool, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = def
+	}
+	var exact []int
+	var classes []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if len(part) == 3 && strings.HasSuffix(part, "xx") && part[0] >= '1' && part[0] <= '5' {
+			classes = append(classes, int(part[0]-'0'))
+			continue
+		}
+		code, err := strconv.Atoi(part)
+		if err != nil || code < 100 || code > 599 {
+			return nil, invalidArgument("%s: invalid status %q", name, part)
+		}
+		exact = append(exact, code)
+	}
+	return func(status int) bool {
+		for _, c := range exact {
+			if c == status {
+				return true
+			}
+		}
+		for _, c := range classes {
+			if status/100 == c {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// statusError 非期望状态码对应的错误；详情中附带状态码和截断的响应体
+func statusError(req *http.Request, resp *http.Response, body []byte, retryable bool) error {
+	snippet := body
+	if len(snippet) > maxErrorBody {
+		snippet = snippet[:maxErrorBody]
+	}
+	msg := fmt.Sprintf("%s %s: unexpected status %s", req.Method, req.URL.Redacted(), resp.Status)
+	opts := temporal.ApplicationErrorOptions{
+		NonRetryable: !retryable,
+		Details:      []interface{}{map[string]interface{}{"status": resp.StatusCode, "body": string(snippet)}},
+	}
+	if retryable {
+		opts.NextRetryDelay = retryAfter(resp.Header.Get("Retry-After"))
+	}
+	return temporal.NewApplicationErrorWithOptions(msg, "HTTPStatus", opts)
+}
+
+// retryAfter 解析 Retry-After（秒数或 HTTP 日期），无法解析时返回 0（使用 RetryPolicy 的间隔）
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// parseBody JSON 响应（Content-Type 含 json，或内容本身是合法 JSON）解析为对象，其余按文本返回
+func parseBody(contentType string, body []byte) interface{} {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return ""
+	}
+	if strings.Contains(contentType, "json") || json.Valid(trimmed) {
+		var v interface{}
+		if err := json.Unmarshal(trimmed, &v); err == nil {
+			return v
+		}
+	}
+	return string(body)
+}
+
+// invalidArgument 参数错误，重试不会成功
+func invalidArgument(format string, args ...interface{}) error {
+	return temporal.NewNonRetryableApplicationError(fmt.Sprintf(format, args...), "InvalidArgument", nil)
+}
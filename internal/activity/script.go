@@ -0,0 +1,333 @@
+package activity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+	"go.temporal.io/sdk/temporal"
+
+	logger "zebra-workflow/internal/log"
+)
+
+const (
+	// scriptSandboxEnv 设置后 worker 进程作为脚本沙箱子进程运行（见 RunScriptSandbox）
+	scriptSandboxEnv = "ZEBRA_SCRIPT_SANDBOX"
+
+	defaultScriptTimeout   = 5 * time.Second
+	defaultScriptMaxMemory = 64 << 20
+	// scriptRuntimeOverhead 子进程中 Go runtime 和 goja 自身占用的内存，加在 maxMemory 之上作为进程数据段上限
+	scriptRuntimeOverhead = 64 << 20
+	// maxScriptOutput 子进程输出上限（结果会进入 workflow history）
+	maxScriptOutput = maxResponseBody
+)
+
+// scriptNamePattern 存储脚本的名称，只允许 scripts.dir 下的文件
+var scriptNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ScriptConfig 映射 scripts
+type ScriptConfig struct {
+	// Dir 存储脚本目录，scriptName 对应 <dir>/<scriptName>.js
+	Dir string `json:"dir,optional" yaml:"dir"`
+	// Timeout 单次执行的 CPU 时间上限，默认 5s
+	Timeout string `json:"timeout,optional" yaml:"timeout"`
+	// MaxMemory 脚本可用的内存（字节），默认 64MiB
+	MaxMemory int64 `json:"maxMemory,optional" yaml:"maxMemory"`
+}
+
+// ScriptRunner 提供 Script 活动，注册时使用指针（活动名为方法名）
+type ScriptRunner struct {
+	dir       string
+	timeout   time.Duration
+	maxMemory int64
+	// executable 沙箱子进程使用的可执行文件，即 worker 自身
+	executable string
+}
+
+// scriptRequest 父进程通过 stdin 发给沙箱子进程的内容
+type scriptRequest struct {
+	Source    string                 `json:"source"`
+	Name      string                 `json:"name"`
+	Input     map[string]interface{} `json:"input"`
+	Timeout   time.Duration          `json:"timeout"`
+	MaxMemory int64                  `json:"maxMemory"`
+}
+
+// scriptResponse 沙箱子进程写到 stdout 的结果
+type scriptResponse struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *scriptError    `json:"error,omitempty"`
+}
+
+// scriptError 脚本抛出的错误，对应 ApplicationError
+type scriptError struct {
+	Type      string      `json:"type"`
+	Message   string      `json:"message"`
+	Retryable bool        `json:"retryable,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// NewScriptRunner 校验配置；脚本在 worker 可执行文件启动的子进程中运行
+func NewScriptRunner(cfg ScriptConfig) (*ScriptRunner, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("scripts: locate worker executable: %w", err)
+	}
+	r := &ScriptRunner{dir: cfg.Dir, timeout: defaultScriptTimeout, maxMemory: defaultScriptMaxMemory, executable: exe}
+	if cfg.Timeout != "" {
+		if r.timeout, err = time.ParseDuration(cfg.Timeout); err != nil || r.timeout <= 0 {
+			return nil, fmt.Errorf("scripts: invalid timeout %q", cfg.Timeout)
+		}
+	}
+	if cfg.MaxMemory > 0 {
+		r.maxMemory = cfg.MaxMemory
+	}
+	return r, nil
+}
+
+// Script 执行 JavaScript（ES5.1 及大部分 ES6，goja 实现）。参数（DSL Arguments，可用 "param=binding" 指定参数名）：
+//
+//	script       内联脚本
+//	scriptName   scripts.dir 下的存储脚本名（不含 .js），与 script 二选一
+//
+// 其余参数作为全局对象 input 传入，JSON 对象 / 数组形式的参数会先解析。脚本定义了 main 函数时返回 main(input)，
+// 否则返回最后一个表达式的值；结果按 JSON.stringify 序列化后作为活动结果。
+// 脚本在独立子进程中运行，没有网络、文件系统和模块加载能力，受 scripts.timeout（CPU 时间）和 scripts.maxMemory 限制。
+//
+// 脚本抛出的错误转换为 ApplicationError：类型为 error.name（throw 的普通对象可设置 type），消息为 message，
+// details 为错误的 details 属性；默认不重试（相同输入结果相同），错误对象设置 retryable: true 时按 RetryPolicy 重试。
+// 超时返回 ScriptTimeout，超出内存返回 ScriptMemoryLimit，语法错误返回 InvalidArgument。
+func (r *ScriptRunner) Script(ctx context.Context, input map[string]string) (interface{}, error) {
+	req := scriptRequest{Timeout: r.timeout, MaxMemory: r.maxMemory}
+	switch {
+	case input["script"] != "" && input["scriptName"] != "":
+		return nil, invalidArgument("script and scriptName are mutually exclusive")
+	case input["script"] != "":
+		req.Source, req.Name = input["script"], "script"
+	case input["scriptName"] != "":
+		name := input["scriptName"]
+		if r.dir == "" {
+			return nil, invalidArgument("scripts.dir is not configured")
+		}
+		if !scriptNamePattern.MatchString(name) {
+			return nil, invalidArgument("invalid scriptName %q", name)
+		}
+		src, err := os.ReadFile(filepath.Join(r.dir, name+".js"))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, invalidArgument("script %q not found", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read script %q: %w", name, err)
+		}
+		req.Source, req.Name = string(src), name+".js"
+	default:
+		return nil, invalidArgument("script or scriptName is required")
+	}
+	req.Input = templateData(input, "script", "scriptName")
+
+	start := time.Now()
+	resp, err := r.run(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	logger.ForActivity(ctx).Infow("script finished", "script", req.Name, "duration", time.Since(start).String(),
+		"failed", resp.Error != nil)
+	if e := resp.Error; e != nil {
+		return nil, temporal.NewApplicationErrorWithOptions(e.Message, e.Type, temporal.ApplicationErrorOptions{
+			NonRetryable: !e.Retryable,
+			Details:      detailsOf(e.Details),
+		})
+	}
+	var result interface{}
+	if len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			return nil, fmt.Errorf("decode script result: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func detailsOf(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+	return []interface{}{v}
+}
+
+// run 启动沙箱子进程并等待结果；子进程因 CPU 或内存上限被终止时转换为对应的错误
+func (r *ScriptRunner) run(ctx context.Context, req scriptRequest) (*scriptResponse, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	// 子进程的 CPU 上限由 rlimit 保证，这里的墙钟超时只兜底（如机器过载）
+	ctx, cancel := context.WithTimeout(ctx, 2*req.Timeout+5*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, r.executable)
+	cmd.Env = []string{scriptSandboxEnv + "=1"}
+	cmd.Dir = os.TempDir()
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr limitedBuffer
+	stdout.limit, stderr.limit = maxScriptOutput, 4096
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	runErr := cmd.Run()
+	if stdout.overflow {
+		return nil, temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("%s: result exceeds %d bytes", req.Name, maxScriptOutput), "ResponseTooLarge", nil)
+	}
+	var resp scriptResponse
+	if stdout.Len() > 0 && json.Unmarshal(stdout.Bytes(), &resp) == nil && (resp.Error != nil || resp.Result != nil) {
+		return &resp, nil
+	}
+	if ctx.Err() != nil && runErr != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, scriptLimitError(req.Name, "ScriptTimeout", "exceeded %s", req.Timeout)
+		}
+		return nil, ctx.Err()
+	}
+	out := stderr.String()
+	switch {
+	case strings.Contains(out, "out of memory") || strings.Contains(out, "cannot allocate memory"):
+		return nil, scriptLimitError(req.Name, "ScriptMemoryLimit", "exceeded %d bytes", req.MaxMemory)
+	case killedByCPULimit(runErr):
+		return nil, scriptLimitError(req.Name, "ScriptTimeout", "exceeded %s of CPU time", req.Timeout)
+	}
+	return nil, fmt.Errorf("script sandbox failed: %v: %s", runErr, strings.TrimSpace(out))
+}
+
+func scriptLimitError(name, errType, format string, args ...interface{}) error {
+	return temporal.NewNonRetryableApplicationError(name+": "+fmt.Sprintf(format, args...), errType, nil)
+}
+
+// limitedBuffer 超过 limit 后丢弃后续输出并记录 overflow
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		b.overflow = true
+		b.Buffer.Write(p[:max(0, b.limit-b.Len())])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// IsScriptSandbox 当前进程是否为脚本沙箱子进程；worker 的 main 需在读取配置前检查
+func IsScriptSandbox() bool {
+	return os.Getenv(scriptSandboxEnv) == "1"
+}
+
+// RunScriptSandbox 沙箱子进程入口：从 stdin 读取 scriptRequest，设置资源上限后执行脚本，把 scriptResponse 写到 stdout。
+// 返回进程退出码
+func RunScriptSandbox() int {
+	var req scriptRequest
+	if err := json.NewDecoder(io.LimitReader(os.Stdin, 64<<20)).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, "script sandbox: read request:", err)
+		return 1
+	}
+	if err := setScriptLimits(req.Timeout, req.MaxMemory+scriptRuntimeOverhead); err != nil {
+		fmt.Fprintln(os.Stderr, "script sandbox: set limits:", err)
+		return 1
+	}
+	// 让 GC 在接近上限前更积极地回收，硬上限由 rlimit 保证
+	debug.SetMemoryLimit(req.MaxMemory)
+
+	resp := evalScript(req)
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		fmt.Fprintln(os.Stderr, "script sandbox: write response:", err)
+		return 1
+	}
+	return 0
+}
+
+// evalScript 在新的 goja runtime 中执行脚本，runtime 只有 ECMAScript 内置对象
+func evalScript(req scriptRequest) scriptResponse {
+	vm := goja.New()
+	vm.SetFieldNameMapper(goja.TagFieldNameMapper("json", true))
+	vm.SetMaxCallStackSize(1024)
+	timer := time.AfterFunc(req.Timeout, func() { vm.Interrupt("timeout") })
+	defer timer.Stop()
+
+	if err := vm.Set("input", req.Input); err != nil {
+		return errorResponse("ScriptError", err.Error())
+	}
+	prog, err := goja.Compile(req.Name, req.Source, true)
+	if err != nil {
+		return errorResponse("InvalidArgument", err.Error())
+	}
+	value, err := vm.RunProgram(prog)
+	if err == nil {
+		if main, ok := goja.AssertFunction(vm.Get("main")); ok {
+			value, err = main(goja.Undefined(), vm.Get("input"))
+		}
+	}
+	if err != nil {
+		return thrownError(req, err)
+	}
+
+	stringify, _ := goja.AssertFunction(vm.Get("JSON").ToObject(vm).Get("stringify"))
+	encoded, err := stringify(goja.Undefined(), value)
+	if err != nil {
+		return thrownError(req, err)
+	}
+	if goja.IsUndefined(encoded) {
+		return scriptResponse{Result: json.RawMessage("null")}
+	}
+	return scriptResponse{Result: json.RawMessage(encoded.String())}
+}
+
+// thrownError 把脚本异常转换为 scriptError：Error 对象取 name / message，普通对象取 type / message，
+// 两者都可带 retryable 和 details
+func thrownError(req scriptRequest, err error) scriptResponse {
+	var interrupted *goja.InterruptedError
+	if errors.As(err, &interrupted) {
+		return errorResponse("ScriptTimeout", fmt.Sprintf("%s: exceeded %s", req.Name, req.Timeout))
+	}
+	var stackOverflow *goja.StackOverflowError
+	if errors.As(err, &stackOverflow) {
+		return errorResponse("RangeError", req.Name+": maximum call stack size exceeded")
+	}
+	var exc *goja.Exception
+	if !errors.As(err, &exc) {
+		return errorResponse("ScriptError", err.Error())
+	}
+	e := &scriptError{Type: "ScriptError", Message: exc.Error()}
+	if obj, ok := exc.Value().(*goja.Object); ok {
+		for _, key := range []string{"name", "type"} {
+			if v := obj.Get(key); v != nil && !goja.IsUndefined(v) && v.String() != "" {
+				e.Type = v.String()
+			}
+		}
+		if v := obj.Get("message"); v != nil && !goja.IsUndefined(v) {
+			e.Message = v.String()
+		}
+		if v := obj.Get("retryable"); v != nil {
+			e.Retryable = v.ToBoolean()
+		}
+		if v := obj.Get("details"); v != nil && !goja.IsUndefined(v) {
+			e.Details = v.Export()
+		}
+	} else if exc.Value() != nil {
+		e.Message = exc.Value().String()
+	}
+	return scriptResponse{Error: e}
+}
+
+func errorResponse(errType, msg string) scriptResponse {
+	return scriptResponse{Error: &scriptError{Type: errType, Message: msg}}
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+
+package activity
+
+import "time"
+
+// setScriptLimits 当前平台不支持 rlimit，只依赖 goja 的执行超时
+func setScriptLimits(cpu time.Duration, memory int64) error {
+	return nil
+}
+
+func killedByCPULimit(err error) bool {
+	return false
+}
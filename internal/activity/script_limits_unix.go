@@ -0,0 +1,29 @@
+//go:build linux || darwin
+
+package activity
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// setScriptLimits 为沙箱子进程设置 CPU 时间（秒，向上取整；超出软上限收到 SIGXCPU，硬上限 SIGKILL）和数据段上限
+func setScriptLimits(cpu time.Duration, memory int64) error {
+	secs := uint64((cpu + time.Second - 1) / time.Second)
+	if err := syscall.Setrlimit(syscall.RLIMIT_CPU, &syscall.Rlimit{Cur: secs, Max: secs + 1}); err != nil {
+		return err
+	}
+	return syscall.Setrlimit(syscall.RLIMIT_DATA, &syscall.Rlimit{Cur: uint64(memory), Max: uint64(memory)})
+}
+
+// killedByCPULimit 子进程是否因 RLIMIT_CPU 被终止
+func killedByCPULimit(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	ws, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && ws.Signaled() && (ws.Signal() == syscall.SIGXCPU || ws.Signal() == syscall.SIGKILL)
+}
@@ -0,0 +1,477 @@
+package activity
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.temporal.io/sdk/temporal"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	logger "zebra-workflow/internal/log"
+)
+
+const (
+	defaultSQLTimeout = 30 * time.Second
+	defaultMaxRows    = 1000
+)
+
+// DatabaseConfig 映射 databases.<name>
+type DatabaseConfig struct {
+	// Driver postgres / mysql / sqlite
+	Driver string `json:"driver" yaml:"driver"`
+	// DSN 连接串；包含密码时改用 DSNEnv
+	DSN    string `json:"dsn,optional" yaml:"dsn"`
+	DSNEnv string `json:"dsnEnv,optional" yaml:"dsnEnv"`
+	// MaxOpenConns 默认 10
+	MaxOpenConns    int    `json:"maxOpenConns,optional" yaml:"maxOpenConns"`
+	MaxIdleConns    int    `json:"maxIdleConns,optional" yaml:"maxIdleConns"`
+	ConnMaxLifetime string `json:"connMaxLifetime,optional" yaml:"connMaxLifetime"`
+}
+
+// SQLStatement statements 参数中的一条语句
+type SQLStatement struct {
+	Query string `json:"query"`
+	// Mode query（返回行）/ exec（返回影响行数），为空时按语句自动判断
+	Mode string `json:"mode"`
+}
+
+// database 一个命名连接
+type database struct {
+	driver string
+	db     *sql.DB
+}
+
+// SQLRunner 提供 SQLQuery 活动，注册时使用指针（活动名为方法名）
+type SQLRunner struct {
+	dbs map[string]*database
+}
+
+// sqlDrivers 配置中的 driver -> database/sql 驱动名
+var sqlDrivers = map[string]string{
+	"postgres": "pgx",
+	"mysql":    "mysql",
+	"sqlite":   "sqlite",
+}
+
+// NewSQLRunner 按配置打开所有命名连接（database/sql 延迟建立连接，这里不会访问数据库）
+func NewSQLRunner(cfgs map[string]DatabaseConfig) (*SQLRunner, error) {
+	r := &SQLRunner{dbs: make(map[string]*database, len(cfgs))}
+	for name, cfg := range cfgs {
+		driverName, ok := sqlDrivers[cfg.Driver]
+		if !ok {
+			r.Close()
+			return nil, fmt.Errorf("databases.%s: unknown driver %q (postgres, mysql or sqlite)", name, cfg.Driver)
+		}
+		dsn := cfg.DSN
+		if cfg.DSNEnv != "" {
+			dsn = os.Getenv(cfg.DSNEnv)
+		}
+		if dsn == "" {
+			r.Close()
+			return nil, fmt.Errorf("databases.%s: dsn or dsnEnv is required", name)
+		}
+		if cfg.DSNEnv != "" {
+			logger.RegisterSecret(dsn)
+		}
+		db, err := sql.Open(driverName, dsn)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("databases.%s: %w", name, err)
+		}
+		maxOpen := cfg.MaxOpenConns
+		if maxOpen <= 0 {
+			maxOpen = 10
+		}
+		db.SetMaxOpenConns(maxOpen)
+		if cfg.MaxIdleConns > 0 {
+			db.SetMaxIdleConns(cfg.MaxIdleConns)
+		}
+		if cfg.ConnMaxLifetime != "" {
+			d, err := time.ParseDuration(cfg.ConnMaxLifetime)
+			if err != nil {
+				db.Close()
+				r.Close()
+				return nil, fmt.Errorf("databases.%s: invalid connMaxLifetime %q", name, cfg.ConnMaxLifetime)
+			}
+			db.SetConnMaxLifetime(d)
+		}
+		r.dbs[name] = &database{driver: cfg.Driver, db: db}
+	}
+	return r, nil
+}
+
+// Close 关闭所有连接池
+func (r *SQLRunner) Close() {
+	for _, d := range r.dbs {
+		d.db.Close()
+	}
+}
+
+// SQLQuery 在命名连接上执行参数化 SQL。参数（DSL Arguments，可用 "param=binding" 指定参数名）：
+//
+//	connection   databases 中的连接名
+//	query        SQL，用 :name 引用其余参数（如 WHERE id = :userId），按驱动转换为 $1 / ? 占位符，值不会拼接进 SQL
+//	statements   JSON 数组 [{"query": "...", "mode": "exec"}, ...]，在同一个事务中依次执行，任一失败整体回滚；与 query 二选一
+//	mode         query（返回行）/ exec（返回影响行数），默认按语句首个关键字判断（SELECT / WITH / SHOW / EXPLAIN 等为 query）
+//	maxRows      最多返回的行数，默认 1000，超过时失败而不是截断
+//	timeout      默认 30s
+//
+// query 返回行数组 [{"col": value}, ...]，exec 返回 {"rowsAffected": n}；statements 返回每条语句结果组成的数组。
+// SQL 语法、约束、数据错误返回类型为 SQLError 的不可重试错误，连接错误按 RetryPolicy 重试。
+// 活动可能被重试，写操作应保持幂等（如 INSERT ... ON CONFLICT DO NOTHING）或放在事务中。
+func (r *SQLRunner) SQLQuery(ctx context.Context, input map[string]string) (interface{}, error) {
+	d, ok := r.dbs[input["connection"]]
+	if !ok {
+		return nil, invalidArgument("unknown connection %q", input["connection"])
+	}
+	maxRows := defaultMaxRows
+	if v := input["maxRows"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, invalidArgument("invalid maxRows %q", v)
+		}
+		maxRows = n
+	}
+	timeout := defaultSQLTimeout
+	if v := input["timeout"]; v != "" {
+		t, err := time.ParseDuration(v)
+		if err != nil || t <= 0 {
+			return nil, invalidArgument("invalid timeout %q", v)
+		}
+		timeout = t
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stmts []SQLStatement
+	switch {
+	case input["query"] != "" && input["statements"] != "":
+		return nil, invalidArgument("query and statements are mutually exclusive")
+	case input["query"] != "":
+		stmts = []SQLStatement{{Query: input["query"], Mode: input["mode"]}}
+	case input["statements"] != "":
+		if err := json.Unmarshal([]byte(input["statements"]), &stmts); err != nil {
+			return nil, invalidArgument("statements must be a JSON array: %v", err)
+		}
+		if len(stmts) == 0 {
+			return nil, invalidArgument("statements is empty")
+		}
+	default:
+		return nil, invalidArgument("query or statements is required")
+	}
+
+	// 先绑定全部语句的参数，参数错误不应开启事务
+	type bound struct {
+		query string
+		args  []interface{}
+		mode  string
+	}
+	prepared := make([]bound, len(stmts))
+	for i, s := range stmts {
+		q, args, err := bindNamed(d.driver, s.Query, input)
+		if err != nil {
+			return nil, err
+		}
+		mode := s.Mode
+		if mode == "" {
+			mode = detectMode(s.Query)
+		}
+		if mode != "query" && mode != "exec" {
+			return nil, invalidArgument("mode must be query or exec, got %q", mode)
+		}
+		prepared[i] = bound{query: q, args: args, mode: mode}
+	}
+
+	start := time.Now()
+	log := logger.ForActivity(ctx)
+	if input["query"] != "" {
+		p := prepared[0]
+		res, err := runStatement(ctx, d.db, p.mode, p.query, p.args, maxRows)
+		if err != nil {
+			return nil, sqlError(err)
+		}
+		log.Infow("sql executed", "connection", input["connection"], "mode", p.mode, "duration", time.Since(start).String())
+		return res, nil
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, sqlError(err)
+	}
+	results := make([]interface{}, len(prepared))
+	for i, p := range prepared {
+		res, err := runStatement(ctx, tx, p.mode, p.query, p.args, maxRows)
+		if err != nil {
+			tx.Rollback()
+			return nil, sqlError(fmt.Errorf("statements[%d]: %w", i, err))
+		}
+		results[i] = res
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, sqlError(err)
+	}
+	log.Infow("sql transaction committed", "connection", input["connection"], "statements", len(prepared),
+		"duration", time.Since(start).String())
+	return results, nil
+}
+
+// queryer *sql.DB 和 *sql.Tx 的公共方法
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func runStatement(ctx context.Context, q queryer, mode, query string, args []interface{}, maxRows int) (interface{}, error) {
+	if mode == "exec" {
+		res, err := q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"rowsAffected": n}, nil
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	out := []map[string]interface{}{}
+	for rows.Next() {
+		if len(out) == maxRows {
+			return nil, temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("query returned more than %d rows", maxRows), "TooManyRows", nil)
+		}
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, c := range cols {
+			row[c] = jsonValue(values[i])
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// jsonValue 把驱动返回的值转换为可 JSON 序列化的形式：[]byte 为字符串，时间为 RFC 3339
+func jsonValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// bindNamed 把 :name 替换为驱动的占位符并按顺序取出参数值；跳过字符串、引号标识符、注释、
+// PostgreSQL 的 :: 类型转换和美元符号引用的字符串（$$...$$、$tag$...$tag$）
+func bindNamed(driver, query string, input map[string]string) (string, []interface{}, error) {
+	var sb strings.Builder
+	var args []interface{}
+	var missing []string
+	// 同一个参数多次出现时 PostgreSQL 复用同一个 $n
+	positions := make(map[string]int)
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(query) {
+				if query[j] == c {
+					if j+1 < len(query) && query[j+1] == c {
+						j += 2
+						continue
+					}
+					break
+				}
+				if query[j] == '\\' && driver == "mysql" {
+					j++
+				}
+				j++
+			}
+			end := min(j+1, len(query))
+			sb.WriteString(query[i:end])
+			i = end
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			j := strings.IndexByte(query[i:], '\n')
+			if j < 0 {
+				j = len(query) - i
+			}
+			sb.WriteString(query[i : i+j])
+			i += j
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			j := strings.Index(query[i+2:], "*/")
+			end := len(query)
+			if j >= 0 {
+				end = i + 2 + j + 2
+			}
+			sb.WriteString(query[i:end])
+			i = end
+		case c == '$' && driver == "postgres" && (i == 0 || !isIdentPart(query[i-1])) && dollarTag(query[i:]) != "":
+			tag := dollarTag(query[i:])
+			end := len(query)
+			if j := strings.Index(query[i+len(tag):], tag); j >= 0 {
+				end = i + len(tag) + j + len(tag)
+			}
+			sb.WriteString(query[i:end])
+			i = end
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			sb.WriteString("::")
+			i += 2
+		case c == ':' && i+1 < len(query) && isIdentStart(query[i+1]):
+			j := i + 1
+			for j < len(query) && isIdentPart(query[j]) {
+				j++
+			}
+			name := query[i+1 : j]
+			v, ok := input[name]
+			if !ok {
+				missing = append(missing, name)
+			}
+			if driver == "postgres" {
+				n, seen := positions[name]
+				if !seen {
+					args = append(args, v)
+					n = len(args)
+					positions[name] = n
+				}
+				sb.WriteString("$" + strconv.Itoa(n))
+			} else {
+				args = append(args, v)
+				sb.WriteByte('?')
+			}
+			i = j
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", nil, invalidArgument("query parameters not passed as arguments: %s", strings.Join(missing, ", "))
+	}
+	return sb.String(), args, nil
+}
+
+// dollarTag 返回 s 开头的美元引用标记（$$ 或 $tag$，tag 不能以数字开头，以区分 $1 占位符），不是时返回空
+func dollarTag(s string) string {
+	k := 1
+	if k < len(s) && isIdentStart(s[k]) {
+		for k < len(s) && isIdentPart(s[k]) {
+			k++
+		}
+	}
+	if k < len(s) && s[k] == '$' {
+		return s[:k+1]
+	}
+	return ""
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+// detectMode 按首个关键字（跳过开头的括号和注释）判断语句是否返回行；带 RETURNING 的写语句需显式指定 mode: query
+func detectMode(query string) string {
+	for {
+		query = strings.TrimLeft(query, "( \t\r\n")
+		if strings.HasPrefix(query, "--") {
+			if j := strings.IndexByte(query, '\n'); j >= 0 {
+				query = query[j+1:]
+				continue
+			}
+			query = ""
+		} else if strings.HasPrefix(query, "/*") {
+			if j := strings.Index(query, "*/"); j >= 0 {
+				query = query[j+2:]
+				continue
+			}
+			query = ""
+		}
+		break
+	}
+	j := 0
+	for j < len(query) && isIdentPart(query[j]) {
+		j++
+	}
+	switch strings.ToUpper(query[:j]) {
+	case "SELECT", "WITH", "SHOW", "EXPLAIN", "VALUES", "PRAGMA", "DESCRIBE", "TABLE":
+		return "query"
+	}
+	return "exec"
+}
+
+// sqlError 语法、约束、数据类错误重试不会成功，返回不可重试错误；其余（连接、超时、死锁等）按 RetryPolicy 重试
+func sqlError(err error) error {
+	var app *temporal.ApplicationError
+	if errors.As(err, &app) {
+		if error(app) == err {
+			return err
+		}
+		// 保留语句位置等外层信息
+		msg := strings.TrimSuffix(err.Error(), app.Error()) + app.Message()
+		return temporal.NewApplicationErrorWithOptions(msg, app.Type(),
+			temporal.ApplicationErrorOptions{NonRetryable: app.NonRetryable()})
+	}
+	if permanentSQLError(err) {
+		return temporal.NewNonRetryableApplicationError(err.Error(), "SQLError", nil)
+	}
+	return err
+}
+
+func permanentSQLError(err error) bool {
+	// PostgreSQL（pgconn.PgError）：22 数据异常、23 约束、42 语法或权限
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) && len(pgErr.SQLState()) >= 2 {
+		switch pgErr.SQLState()[:2] {
+		case "22", "23", "42":
+			return true
+		}
+		return false
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch string(myErr.SQLState[:2]) {
+		case "22", "23", "42":
+			return true
+		}
+		return false
+	}
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		switch liteErr.Code() & 0xff {
+		case sqlite3.SQLITE_ERROR, sqlite3.SQLITE_CONSTRAINT, sqlite3.SQLITE_MISMATCH, sqlite3.SQLITE_RANGE:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package activity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindNamed(t *testing.T) {
+	input := map[string]string{"id": "7", "name": "zebra"}
+	tests := []struct {
+		name, driver, query string
+		want                string
+		args                []interface{}
+	}{
+		{"postgres", "postgres", "SELECT * FROM t WHERE id = :id AND name = :name",
+			"SELECT * FROM t WHERE id = $1 AND name = $2", []interface{}{"7", "zebra"}},
+		{"mysql", "mysql", "SELECT * FROM t WHERE id = :id AND name = :name",
+			"SELECT * FROM t WHERE id = ? AND name = ?", []interface{}{"7", "zebra"}},
+		{"repeated postgres", "postgres", "SELECT :id, :name, :id",
+			"SELECT $1, $2, $1", []interface{}{"7", "zebra"}},
+		{"repeated sqlite", "sqlite", "SELECT :id, :name, :id",
+			"SELECT ?, ?, ?", []interface{}{"7", "zebra", "7"}},
+		{"single quotes", "postgres", "SELECT ':id', 'it''s :name', :id",
+			"SELECT ':id', 'it''s :name', $1", []interface{}{"7"}},
+		{"double quotes", "postgres", `SELECT ":id" FROM t WHERE id = :id`,
+			`SELECT ":id" FROM t WHERE id = $1`, []interface{}{"7"}},
+		{"mysql backslash escape", "mysql", `SELECT 'a\':id', :id`,
+			`SELECT 'a\':id', ?`, []interface{}{"7"}},
+		{"backticks", "mysql", "SELECT `:id` FROM t WHERE id = :id",
+			"SELECT `:id` FROM t WHERE id = ?", []interface{}{"7"}},
+		{"line comment", "postgres", "SELECT :id -- :name\nFROM t",
+			"SELECT $1 -- :name\nFROM t", []interface{}{"7"}},
+		{"block comment", "postgres", "SELECT /* :name */ :id",
+			"SELECT /* :name */ $1", []interface{}{"7"}},
+		{"cast", "postgres", "SELECT :id::int, created::date",
+			"SELECT $1::int, created::date", []interface{}{"7"}},
+		{"dollar quoting", "postgres", "SELECT $$ :name $$, :id",
+			"SELECT $$ :name $$, $1", []interface{}{"7"}},
+		{"tagged dollar quoting", "postgres", "DO $body$ BEGIN PERFORM ':x' || $$ :name $$; END $body$; SELECT :id",
+			"DO $body$ BEGIN PERFORM ':x' || $$ :name $$; END $body$; SELECT $1", []interface{}{"7"}},
+		{"positional is not a tag", "postgres", "SELECT $1, :id",
+			"SELECT $1, $1", []interface{}{"7"}},
+		{"dollar in identifier", "postgres", "SELECT a$b$ FROM t WHERE id = :id",
+			"SELECT a$b$ FROM t WHERE id = $1", []interface{}{"7"}},
+		{"no dollar quoting in mysql", "mysql", "SELECT '$$', :id",
+			"SELECT '$$', ?", []interface{}{"7"}},
+	}
+	for _, tt := range tests {
+		got, args, err := bindNamed(tt.driver, tt.query, input)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: got %q %v, want %q %v", tt.name, got, args, tt.want, tt.args)
+		}
+	}
+
+	if _, _, err := bindNamed("postgres", "SELECT :id, :missing, :other", input); err == nil {
+		t.Error("missing parameters accepted")
+	}
+}
+
+func TestDetectMode(t *testing.T) {
+	tests := map[string]string{
+		"SELECT 1":                              "query",
+		"  select * from t":                     "query",
+		"(SELECT 1) UNION (SELECT 2)":           "query",
+		"WITH x AS (SELECT 1) SELECT * FROM x":  "query",
+		"-- list users\nSELECT * FROM users":    "query",
+		"/* report */ SELECT 1":                 "query",
+		"SELECT(1)":                             "query",
+		"SHOW TABLES":                           "query",
+		"PRAGMA table_info(t)":                  "query",
+		"INSERT INTO t VALUES (1)":              "exec",
+		"UPDATE t SET a = 1":                    "exec",
+		"DELETE FROM t":                         "exec",
+		"-- SELECT\nDELETE FROM t":              "exec",
+		"INSERT INTO t VALUES (1) RETURNING id": "exec",
+		"":                                      "exec",
+		"-- only a comment":                     "exec",
+	}
+	for query, want := range tests {
+		if got := detectMode(query); got != want {
+			t.Errorf("detectMode(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/itchyny/gojq"
+	"go.temporal.io/sdk/temporal"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// templateFuncs RenderTemplate 可用的函数
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join":  func(sep string, v []interface{}) string { return joinValues(v, sep) },
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+}
+
+// RenderTemplate 渲染 Go text/template 并返回结果文本，适合把前面活动的结果拼成下一个活动的参数（URL、请求体、邮件正文）。
+// 参数（DSL Arguments，可用 "param=binding" 指定参数名）：
+//
+//	template   模板，数据为其余全部参数；JSON 对象 / 数组形式的参数（如活动结果）会先解析，可写 {{.r1.title}}
+//	output     text（默认，返回字符串）或 json（把渲染结果解析为 JSON 返回）
+//
+// 除内置函数外可用 json、join、upper、lower、trim、default。结果只依赖参数，可在 DSL 中用 "Local": true 作为本地活动执行。
+func RenderTemplate(ctx context.Context, input map[string]string) (interface{}, error) {
+	if input["template"] == "" {
+		return nil, invalidArgument("template is required")
+	}
+	t, err := template.New("template").Option("missingkey=error").Funcs(templateFuncs).Parse(input["template"])
+	if err != nil {
+		return nil, invalidArgument("%v", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, templateData(input, "template", "output")); err != nil {
+		return nil, invalidArgument("%v", err)
+	}
+	logger.ForActivity(ctx).Debugw("template rendered", "bytes", sb.Len())
+
+	switch input["output"] {
+	case "", "text":
+		return sb.String(), nil
+	case "json":
+		var v interface{}
+		if err := json.Unmarshal([]byte(sb.String()), &v); err != nil {
+			return nil, temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("rendered template is not valid JSON: %v", err), "TransformFailed", nil)
+		}
+		return v, nil
+	default:
+		return nil, invalidArgument("output must be text or json, got %q", input["output"])
+	}
+}
+
+// TransformJSON 用 jq 表达式（gojq 实现）变换 JSON。参数：
+//
+//	expression  jq 表达式，如 {title: .title, tags: [.tags[].name]}
+//	input       输入 JSON（通常是前面活动的 Result），为空时为 null
+//	all         true 时返回全部输出组成的数组，默认只返回第一个输出（没有输出时为 null）
+//
+// 其余参数作为 jq 变量传入（$name），JSON 对象 / 数组会先解析。表达式错误返回 InvalidArgument，
+// 执行错误返回 TransformFailed，均不重试。结果只依赖参数，可在 DSL 中用 "Local": true 作为本地活动执行。
+func TransformJSON(ctx context.Context, input map[string]string) (interface{}, error) {
+	if input["expression"] == "" {
+		return nil, invalidArgument("expression is required")
+	}
+	query, err := gojq.Parse(input["expression"])
+	if err != nil {
+		return nil, invalidArgument("expression: %v", err)
+	}
+
+	vars := templateData(input, "expression", "input", "all")
+	names := make([]string, 0, len(vars))
+	for k := range vars {
+		if isIdentifier(k) {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	values := make([]interface{}, len(names))
+	for i, k := range names {
+		values[i] = vars[k]
+		names[i] = "$" + k
+	}
+	code, err := gojq.Compile(query, gojq.WithVariables(names))
+	if err != nil {
+		return nil, invalidArgument("expression: %v", err)
+	}
+
+	var in interface{}
+	if raw := strings.TrimSpace(input["input"]); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &in); err != nil {
+			return nil, invalidArgument("input is not valid JSON: %v", err)
+		}
+	}
+
+	var results []interface{}
+	iter := code.RunWithContext(ctx, in, values...)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("jq: %v", err), "TransformFailed", nil)
+		}
+		results = append(results, v)
+		if input["all"] != "true" {
+			break
+		}
+	}
+	logger.ForActivity(ctx).Debugw("json transformed", "results", len(results))
+	if input["all"] == "true" {
+		if results == nil {
+			results = []interface{}{}
+		}
+		return results, nil
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	return results[0], nil
+}
+
+// templateData 去掉 exclude 中的控制参数，JSON 对象 / 数组形式的值解析后返回
+func templateData(input map[string]string, exclude ...string) map[string]interface{} {
+	data := make(map[string]interface{}, len(input))
+	for k, v := range input {
+		data[k] = v
+		t := strings.TrimSpace(v)
+		if strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[") {
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(t), &parsed); err == nil {
+				data[k] = parsed
+			}
+		}
+	}
+	for _, k := range exclude {
+		delete(data, k)
+	}
+	return data
+}
+
+// isIdentifier jq 变量名只能由字母、数字、下划线组成且不以数字开头
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func joinValues(v []interface{}, sep string) string {
+	parts := make([]string, len(v))
+	for i, e := range v {
+		parts[i] = fmt.Sprint(e)
+	}
+	return strings.Join(parts, sep)
+}
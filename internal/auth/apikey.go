@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIKeyConfig 静态 API key，只保存 key 的 SHA-256（hex），
+// 生成方式：echo -n "<key>" | sha256sum
+type APIKeyConfig struct {
+	Name   string   `yaml:"name" json:"name"`
+	SHA256 string   `yaml:"sha256" json:"sha256"`
+	Roles  []string `yaml:"roles" json:"roles,optional"`
+}
+
+type apiKey struct {
+	name  string
+	hash  []byte
+	roles []string
+}
+
+// apiKeyAuthenticator 从 X-API-Key 或 Authorization: Bearer <key> 中读取 API key
+type apiKeyAuthenticator struct {
+	keys []apiKey
+}
+
+func newAPIKeyAuthenticator(cfgs []APIKeyConfig) (*apiKeyAuthenticator, error) {
+	a := &apiKeyAuthenticator{}
+	for _, c := range cfgs {
+		if c.Name == "" {
+			return nil, errors.New("api key without name")
+		}
+		h, err := hex.DecodeString(strings.TrimSpace(c.SHA256))
+		if err != nil || len(h) != sha256.Size {
+			return nil, fmt.Errorf("api key %q: sha256 must be a hex encoded SHA-256 digest", c.Name)
+		}
+		a.keys = append(a.keys, apiKey{name: c.Name, hash: h, roles: c.Roles})
+	}
+	return a, nil
+}
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		if t := bearerToken(r); t != "" && !looksLikeJWT(t) {
+			key = t
+		}
+	}
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	sum := sha256.Sum256([]byte(key))
+	// 遍历全部 key，避免按匹配位置泄露耗时信息
+	var matched *apiKey
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare(sum[:], a.keys[i].hash) == 1 {
+			matched = &a.keys[i]
+		}
+	}
+	if matched == nil {
+		return nil, errors.New("invalid api key")
+	}
+	return &Principal{Method: "apikey", Subject: matched.name, Roles: matched.roles}, nil
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	sum := sha256.Sum256([]byte("ci-secret"))
+	a, err := newAPIKeyAuthenticator([]APIKeyConfig{{Name: "ci", SHA256: hex.EncodeToString(sum[:]), Roles: []string{"admin"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		header, value string
+		subject       string
+		err           error
+	}{
+		"X-API-Key":      {"X-API-Key", "ci-secret", "ci", nil},
+		"bearer":         {"Authorization", "Bearer ci-secret", "ci", nil},
+		"bad key":        {"X-API-Key", "ci-secret2", "", errInvalid},
+		"bad bearer key": {"Authorization", "Bearer wrong", "", errInvalid},
+		"jwt bearer":     {"Authorization", "Bearer a.b.c", "", ErrNoCredentials},
+		"no credentials": {"", "", "", ErrNoCredentials},
+	}
+	for name, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/v1/workflows", nil)
+		if tt.header != "" {
+			r.Header.Set(tt.header, tt.value)
+		}
+		p, err := a.Authenticate(r)
+		switch {
+		case tt.err == nil:
+			if err != nil || p.Subject != tt.subject || p.Method != "apikey" {
+				t.Errorf("%s: principal = %+v, err = %v", name, p, err)
+			}
+		case tt.err == errInvalid:
+			if err == nil || errors.Is(err, ErrNoCredentials) {
+				t.Errorf("%s: err = %v, want invalid api key", name, err)
+			}
+		case !errors.Is(err, tt.err):
+			t.Errorf("%s: err = %v, want %v", name, err, tt.err)
+		}
+	}
+}
+
+// errInvalid 表示期望凭证被拒绝（而不是没有凭证）
+var errInvalid = errors.New("invalid")
+
+func TestNewAPIKeyAuthenticatorRejectsInvalidConfig(t *testing.T) {
+	for name, cfg := range map[string]APIKeyConfig{
+		"no name":    {SHA256: hex.EncodeToString(make([]byte, sha256.Size))},
+		"not hex":    {Name: "ci", SHA256: "zz"},
+		"short hash": {Name: "ci", SHA256: "abcd"},
+		"plain key":  {Name: "ci", SHA256: "ci-secret"},
+	} {
+		if _, err := newAPIKeyAuthenticator([]APIKeyConfig{cfg}); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// Config 映射 configs/config.yaml 中的 auth 段
+type Config struct {
+	Enabled bool           `yaml:"enabled" json:"enabled,optional"`
+	APIKeys []APIKeyConfig `yaml:"apiKeys" json:"apiKeys,optional"`
+	JWT     JWTConfig      `yaml:"jwt" json:"jwt,optional"`
+	// Roles 角色 -> 权限；为空时认证通过即可执行所有操作
+	Roles map[string][]Grant `yaml:"roles" json:"roles,optional"`
+}
+
+// Principal 认证通过的调用方
+type Principal struct {
+	// Method: "apikey" 或 "jwt"
+	Method  string
+	Subject string
+	// Roles 来自 API key 配置或 JWT 的角色 claim
+	Roles []string
+	// Tenant 请求选择的租户（SelectTenant 设置），为空表示默认 namespace
+	Tenant string
+
+	policy *policy
+}
+
+// String 返回 "<method>:<subject>"，用于日志和 workflow memo 中的 startedBy
+func (p *Principal) String() string {
+	return p.Method + ":" + p.Subject
+}
+
+// ErrNoCredentials 请求未携带当前认证方式能识别的凭证，由下一个 Authenticator 继续尝试
+var ErrNoCredentials = errors.New("no credentials")
+
+// Authenticator 从请求中识别调用方。
+// 返回 ErrNoCredentials 表示请求中没有该方式的凭证；其他错误表示凭证无效，直接拒绝。
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Chain 依次尝试多个 Authenticator，第一个识别出凭证的结果生效
+type Chain []Authenticator
+
+func (c Chain) Authenticate(r *http.Request) (*Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// New 按配置创建 Authenticator；未启用时返回 nil（Middleware 放行所有请求）
+func New(cfg Config) (Authenticator, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	var chain Chain
+	if len(cfg.APIKeys) > 0 {
+		a, err := newAPIKeyAuthenticator(cfg.APIKeys)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, a)
+	}
+	if cfg.JWT.JWKSFile != "" || cfg.JWT.JWKSURL != "" {
+		a, err := newJWTAuthenticator(cfg.JWT)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, a)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("auth is enabled but neither apiKeys nor jwt is configured")
+	}
+	pol, err := newPolicy(cfg.Roles)
+	if err != nil {
+		return nil, err
+	}
+	return &authorizing{next: chain, policy: pol}, nil
+}
+
+// authorizing 为认证通过的 Principal 附上授权策略，供 logic 层的 Authorize 使用
+type authorizing struct {
+	next   Authenticator
+	policy *policy
+}
+
+func (a *authorizing) Authenticate(r *http.Request) (*Principal, error) {
+	p, err := a.next.Authenticate(r)
+	if err != nil {
+		return nil, err
+	}
+	p.policy = a.policy
+	return p, nil
+}
+
+type principalKey struct{}
+
+// WithPrincipal 把调用方放入 context，供 logic 层读取
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext 返回 context 中的调用方；未启用认证时为 nil
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// Middleware 返回认证中间件：认证失败返回 401，成功后把 Principal 放入 r.Context()。
+// authn 为 nil（未启用认证）时直接放行。
+func Middleware(authn Authenticator) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		if authn == nil {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) {
+			p, err := authn.Authenticate(r)
+			if err != nil {
+				if !errors.Is(err, ErrNoCredentials) {
+					logger.Sugar.Warnw("authentication failed", "path", r.URL.Path, "remote", r.RemoteAddr, "err", err)
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer realm="zebra-workflow"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		}
+	}
+}
+
+// bearerToken 返回 Authorization: Bearer 后的 token
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if len(h) > 7 && strings.EqualFold(h[:7], "bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+	return ""
+}
+
+// looksLikeJWT JWT 由三段 base64url 组成，API key 不含 "."
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
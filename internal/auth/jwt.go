@@ -0,0 +1,279 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/sync/singleflight"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// JWTConfig 校验 Bearer JWT：签名公钥来自 JWKS 文件或 URL（二选一），issuer / audience 为空时不校验
+type JWTConfig struct {
+	JWKSFile string `yaml:"jwksFile" json:"jwksFile,optional"`
+	JWKSURL  string `yaml:"jwksUrl" json:"jwksUrl,optional"`
+	Issuer   string `yaml:"issuer" json:"issuer,optional"`
+	Audience string `yaml:"audience" json:"audience,optional"`
+	// SubjectClaim 作为 Principal.Subject 的 claim，默认 "sub"
+	SubjectClaim string `yaml:"subjectClaim" json:"subjectClaim,optional"`
+	// RolesClaim 角色 claim（字符串数组或字符串），默认 "roles"
+	RolesClaim string `yaml:"rolesClaim" json:"rolesClaim,optional"`
+	// RefreshInterval JWKS URL 的刷新间隔，默认 1h；遇到未知 kid 时也会刷新（至多每分钟一次）
+	RefreshInterval string `yaml:"refreshInterval" json:"refreshInterval,optional"`
+}
+
+// 只接受非对称签名算法，避免用公钥作为 HMAC 密钥伪造 token
+var jwtMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+type jwtAuthenticator struct {
+	cfg    JWTConfig
+	keys   *jwks
+	parser *jwt.Parser
+}
+
+func newJWTAuthenticator(cfg JWTConfig) (*jwtAuthenticator, error) {
+	if cfg.JWKSFile != "" && cfg.JWKSURL != "" {
+		return nil, errors.New("jwt: set either jwksFile or jwksUrl, not both")
+	}
+	if cfg.SubjectClaim == "" {
+		cfg.SubjectClaim = "sub"
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	refresh := time.Hour
+	if cfg.RefreshInterval != "" {
+		d, err := time.ParseDuration(cfg.RefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid refreshInterval %q: %w", cfg.RefreshInterval, err)
+		}
+		refresh = d
+	}
+	keys := &jwks{file: cfg.JWKSFile, url: cfg.JWKSURL, refresh: refresh, client: &http.Client{Timeout: 10 * time.Second}}
+	if err := keys.load(); err != nil {
+		return nil, err
+	}
+	return &jwtAuthenticator{
+		cfg:    cfg,
+		keys:   keys,
+		parser: jwt.NewParser(jwt.WithValidMethods(jwtMethods)),
+	}, nil
+}
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	raw := bearerToken(r)
+	if raw == "" || !looksLikeJWT(raw) {
+		return nil, ErrNoCredentials
+	}
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.key(kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt: %w", err)
+	}
+	// 解析时只在 exp 存在时校验，这里要求必须有 exp，不接受永不过期的 token
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("invalid jwt: missing exp claim")
+	}
+	if a.cfg.Issuer != "" && !claims.VerifyIssuer(a.cfg.Issuer, true) {
+		return nil, fmt.Errorf("invalid jwt: unexpected issuer %v", claims["iss"])
+	}
+	if a.cfg.Audience != "" && !claims.VerifyAudience(a.cfg.Audience, true) {
+		return nil, fmt.Errorf("invalid jwt: audience does not include %q", a.cfg.Audience)
+	}
+	subject, _ := claims[a.cfg.SubjectClaim].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("invalid jwt: missing %q claim", a.cfg.SubjectClaim)
+	}
+	return &Principal{Method: "jwt", Subject: subject, Roles: rolesOf(claims[a.cfg.RolesClaim])}, nil
+}
+
+// jwks 缓存按 kid 索引的公钥
+type jwks struct {
+	file    string
+	url     string
+	refresh time.Duration
+	client  *http.Client
+
+	// loads 合并并发的刷新，未知 kid 的请求同时到达时只读取一次
+	loads     singleflight.Group
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// key 返回 kid 对应的公钥；token 没有 kid 且只有一个 key 时使用该 key
+func (k *jwks) key(kid string) (interface{}, error) {
+	k.mu.RLock()
+	key, ok := k.lookup(kid)
+	stale := k.url != "" && time.Since(k.fetchedAt) > k.refresh
+	recent := time.Since(k.fetchedAt) < time.Minute
+	k.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+	if !ok && recent {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if _, err, _ := k.loads.Do("", func() (interface{}, error) { return nil, k.load() }); err != nil {
+		// 刷新失败时继续使用旧的 key
+		logger.Sugar.Warnw("reload jwks failed", "err", err)
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func (k *jwks) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(k.keys) == 1 {
+		for _, v := range k.keys {
+			return v, true
+		}
+	}
+	v, ok := k.keys[kid]
+	return v, ok
+}
+
+func (k *jwks) load() error {
+	var data []byte
+	var err error
+	if k.file != "" {
+		data, err = os.ReadFile(k.file)
+		if err != nil {
+			return fmt.Errorf("read jwks file: %w", err)
+		}
+	} else {
+		data, err = k.fetch()
+		if err != nil {
+			return err
+		}
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+func (k *jwks) fetch() ([]byte, error) {
+	resp, err := k.client.Get(k.url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS 解析 RSA / EC 签名公钥，忽略其他类型和用途为加密（use=enc）的 key
+func parseJWKS(data []byte) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jk := range set.Keys {
+		if jk.Use != "" && jk.Use != "sig" {
+			continue
+		}
+		var key interface{}
+		var err error
+		switch jk.Kty {
+		case "RSA":
+			key, err = rsaKey(jk)
+		case "EC":
+			key, err = ecKey(jk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse jwks key %q: %w", jk.Kid, err)
+		}
+		keys[jk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+func rsaKey(jk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid n: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jk.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid e: %w", err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
+
+func ecKey(jk jsonWebKey) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", jk.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(jk.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y: %w", err)
+	}
+	key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("point is not on curve")
+	}
+	return key, nil
+}
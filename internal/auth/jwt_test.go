@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func rsaJWK(kid string, key *rsa.PublicKey) jsonWebKey {
+	return jsonWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func jwksJSON(t *testing.T, keys ...jsonWebKey) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwksJSON(t, rsaJWK("k1", &key.PublicKey)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := newJWTAuthenticator(JWTConfig{JWKSFile: path, Issuer: "https://idp", Audience: "zebra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"sub": "alice", "iss": "https://idp", "aud": "zebra", "exp": exp, "roles": []string{"viewer"}}
+	}
+	with := func(k string, v interface{}) jwt.MapClaims {
+		c := valid()
+		if v == nil {
+			delete(c, k)
+		} else {
+			c[k] = v
+		}
+		return c
+	}
+	sign := func(kid string, claims jwt.MapClaims) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		tok.Header["kid"] = kid
+		s, err := tok.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	// 用公开的公钥作为 HMAC 密钥伪造的 token
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString(key.PublicKey.N.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		token string
+		ok    bool
+	}{
+		"valid":           {sign("k1", valid()), true},
+		"no kid":          {sign("", valid()), true},
+		"forged HS256":    {forged, false},
+		"alg none":        {unsigned, false},
+		"expired":         {sign("k1", with("exp", time.Now().Add(-time.Minute).Unix())), false},
+		"no exp":          {sign("k1", with("exp", nil)), false},
+		"wrong issuer":    {sign("k1", with("iss", "https://other")), false},
+		"wrong audience":  {sign("k1", with("aud", "other")), false},
+		"unknown kid":     {sign("k2", valid()), false},
+		"missing subject": {sign("k1", with("sub", nil)), false},
+	}
+	for name, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/v1/workflows", nil)
+		r.Header.Set("Authorization", "Bearer "+tt.token)
+		p, err := a.Authenticate(r)
+		if tt.ok {
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			} else if p.Subject != "alice" || len(p.Roles) != 1 || p.Roles[0] != "viewer" {
+				t.Errorf("%s: principal = %+v", name, p)
+			}
+			continue
+		}
+		if err == nil || errors.Is(err, ErrNoCredentials) {
+			t.Errorf("%s: err = %v, want invalid jwt", name, err)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/v1/workflows", nil)
+	r.Header.Set("Authorization", "Bearer not-a-jwt")
+	if _, err := a.Authenticate(r); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("api key bearer: err = %v, want ErrNoCredentials", err)
+	}
+}
+
+func TestJWKSReloadIsShared(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		w.Write(jwksJSON(t, rsaJWK("k1", &key.PublicKey)))
+	}))
+	defer srv.Close()
+	keys := &jwks{url: srv.URL, refresh: time.Hour, client: srv.Client()}
+	if err := keys.load(); err != nil {
+		t.Fatal(err)
+	}
+	// 使刷新到期，并发请求只触发一次读取
+	keys.fetchedAt = time.Now().Add(-2 * time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := keys.key("k1"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	for fetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("jwks fetched %d times, want 2", n)
+	}
+}
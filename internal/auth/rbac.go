@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+)
+
+// Operation 授权检查的操作
+type Operation string
+
+const (
+	OpStart     Operation = "start"
+	OpSignal    Operation = "signal"
+	OpQuery     Operation = "query"
+	OpCancel    Operation = "cancel"
+	OpTerminate Operation = "terminate"
+	OpReset     Operation = "reset"
+	OpSchedule  Operation = "schedule"
+	OpDefine    Operation = "define"
+	// OpCodec 调用 /codec/encode、/codec/decode，payload 可能来自任何 workflow，按 Workflow("*") 检查
+	OpCodec Operation = "codec"
+)
+
+var operations = map[Operation]bool{
+	OpStart: true, OpSignal: true, OpQuery: true, OpCancel: true,
+	OpTerminate: true, OpReset: true, OpSchedule: true, OpDefine: true,
+	OpCodec: true,
+}
+
+// Resource 被操作的对象：workflow（按 workflow 类型名）或 DSL 定义（按定义名）
+type Resource struct {
+	Kind string
+	Name string
+}
+
+const (
+	KindWorkflow   = "workflow"
+	KindDefinition = "definition"
+)
+
+// Workflow 返回 workflow 类型对应的资源
+func Workflow(name string) Resource { return Resource{Kind: KindWorkflow, Name: name} }
+
+// Definition 返回 DSL 定义对应的资源
+func Definition(name string) Resource { return Resource{Kind: KindDefinition, Name: name} }
+
+// Grant 角色授予的权限：operations 作用于匹配 workflows / definitions 模式（path.Match 语法，如 "Billing*"）的资源，
+// operations 可用 "*" 表示全部操作。
+// tenants 为空时 grant 只作用于未选择租户的请求（默认 namespace），否则只作用于租户名匹配的请求
+type Grant struct {
+	Operations  []string `yaml:"operations" json:"operations"`
+	Workflows   []string `yaml:"workflows" json:"workflows,optional"`
+	Definitions []string `yaml:"definitions" json:"definitions,optional"`
+	Tenants     []string `yaml:"tenants" json:"tenants,optional"`
+}
+
+// ForbiddenError 授权失败，Permission 为缺少的权限（如 "cancel:workflow/BillingFlow"），handler 层映射为 403
+type ForbiddenError struct {
+	Principal  string
+	Permission string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("forbidden: %s is missing permission %q", e.Principal, e.Permission)
+}
+
+// policy 角色 -> 权限
+type policy struct {
+	roles map[string][]Grant
+}
+
+func newPolicy(roles map[string][]Grant) (*policy, error) {
+	if len(roles) == 0 {
+		return nil, nil
+	}
+	for name, grants := range roles {
+		for _, g := range grants {
+			for _, op := range g.Operations {
+				if op != "*" && !operations[Operation(op)] {
+					return nil, fmt.Errorf("role %q: unknown operation %q", name, op)
+				}
+			}
+			patterns := append(append(append([]string(nil), g.Workflows...), g.Definitions...), g.Tenants...)
+			for _, p := range patterns {
+				if _, err := path.Match(p, ""); err != nil {
+					return nil, fmt.Errorf("role %q: invalid pattern %q: %w", name, p, err)
+				}
+			}
+		}
+	}
+	return &policy{roles: roles}, nil
+}
+
+func (p *policy) allows(roles []string, tenant string, op Operation, res Resource) bool {
+	for _, role := range roles {
+		for _, g := range p.roles[role] {
+			if !matchTenant(g.Tenants, tenant) || !matchOperation(g.Operations, op) {
+				continue
+			}
+			patterns := g.Workflows
+			if res.Kind == KindDefinition {
+				patterns = g.Definitions
+			}
+			for _, pattern := range patterns {
+				if ok, _ := path.Match(pattern, res.Name); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// allowsTenant 角色中是否有作用于 tenant 的 grant
+func (p *policy) allowsTenant(roles []string, tenant string) bool {
+	for _, role := range roles {
+		for _, g := range p.roles[role] {
+			if matchTenant(g.Tenants, tenant) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchTenant(patterns []string, tenant string) bool {
+	if tenant == "" {
+		return len(patterns) == 0
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, tenant); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchOperation(ops []string, op Operation) bool {
+	for _, o := range ops {
+		if o == "*" || Operation(o) == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Enforced 当前请求是否需要授权检查（启用了认证且配置了 roles）。
+// logic 层在检查前需要额外查询（如按 workflowId 查 workflow 类型）时，先用它判断，避免无谓的调用。
+func Enforced(ctx context.Context) bool {
+	p := FromContext(ctx)
+	return p != nil && p.policy != nil
+}
+
+// Authorize 检查调用方是否可以对 res 执行 op，未启用认证或未配置 roles 时总是通过
+func Authorize(ctx context.Context, op Operation, res Resource) error {
+	p := FromContext(ctx)
+	if p == nil || p.policy == nil {
+		return nil
+	}
+	if p.policy.allows(p.Roles, p.Tenant, op, res) {
+		return nil
+	}
+	return &ForbiddenError{
+		Principal:  p.String(),
+		Permission: fmt.Sprintf("%s:%s/%s", op, res.Kind, res.Name),
+	}
+}
+
+// SelectTenant 检查调用方是否可以使用 tenant，通过时返回的 context 中 Principal 记录该租户，
+// 之后的 Authorize 只使用作用于该租户的 grant。未启用认证或未配置 roles 时总是通过
+func SelectTenant(ctx context.Context, tenant string) (context.Context, error) {
+	p := FromContext(ctx)
+	if p == nil || p.policy == nil {
+		return ctx, nil
+	}
+	if !p.policy.allowsTenant(p.Roles, tenant) {
+		return ctx, &ForbiddenError{Principal: p.String(), Permission: "tenant/" + tenant}
+	}
+	scoped := *p
+	scoped.Tenant = tenant
+	return WithPrincipal(ctx, &scoped), nil
+}
+
+// rolesOf 把 JWT 中的角色 claim（字符串数组或单个字符串）转换为角色列表
+func rolesOf(claim interface{}) []string {
+	var roles []string
+	switch v := claim.(type) {
+	case string:
+		if v != "" {
+			roles = append(roles, v)
+		}
+	case []interface{}:
+		for _, r := range v {
+			if s, ok := r.(string); ok && s != "" {
+				roles = append(roles, s)
+			}
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func testPolicy(t *testing.T) *policy {
+	t.Helper()
+	p, err := newPolicy(map[string][]Grant{
+		"admin": {{Operations: []string{"*"}, Workflows: []string{"*"}, Definitions: []string{"*"}}},
+		"billing": {
+			{Operations: []string{"start", "signal", "query"}, Workflows: []string{"Billing*"}},
+			{Operations: []string{"define"}, Definitions: []string{"Billing*"}},
+		},
+		"billing-tenant": {{Operations: []string{"query"}, Workflows: []string{"*"}, Tenants: []string{"billing", "billing-*"}}},
+		"viewer":         {{Operations: []string{"query"}, Workflows: []string{"*"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPolicyAllows(t *testing.T) {
+	p := testPolicy(t)
+	tests := []struct {
+		roles  []string
+		tenant string
+		op     Operation
+		res    Resource
+		want   bool
+	}{
+		{[]string{"admin"}, "", OpTerminate, Workflow("Anything"), true},
+		{[]string{"admin"}, "", OpDefine, Definition("AnyDefinition"), true},
+		{[]string{"billing"}, "", OpStart, Workflow("BillingFlow"), true},
+		{[]string{"billing"}, "", OpStart, Workflow("Billing"), true},
+		{[]string{"billing"}, "", OpStart, Workflow("ShippingFlow"), false},
+		{[]string{"billing"}, "", OpCancel, Workflow("BillingFlow"), false},
+		{[]string{"billing"}, "", OpReset, Workflow("BillingFlow"), false},
+		// workflow 模式不作用于定义，反之亦然
+		{[]string{"billing"}, "", OpDefine, Definition("BillingInvoice"), true},
+		{[]string{"billing"}, "", OpDefine, Definition("ShippingInvoice"), false},
+		{[]string{"billing"}, "", OpDefine, Workflow("BillingInvoice"), false},
+		{[]string{"viewer"}, "", OpQuery, Definition("BillingInvoice"), false},
+		{[]string{"viewer", "billing"}, "", OpSignal, Workflow("BillingFlow"), true},
+		{[]string{"unknown"}, "", OpQuery, Workflow("BillingFlow"), false},
+		{nil, "", OpQuery, Workflow("BillingFlow"), false},
+		// 没有 tenants 的 grant 只作用于默认 namespace
+		{[]string{"admin"}, "billing", OpQuery, Workflow("BillingFlow"), false},
+		{[]string{"billing-tenant"}, "billing", OpQuery, Workflow("BillingFlow"), true},
+		{[]string{"billing-tenant"}, "billing-eu", OpQuery, Workflow("BillingFlow"), true},
+		{[]string{"billing-tenant"}, "shipping", OpQuery, Workflow("BillingFlow"), false},
+		{[]string{"billing-tenant"}, "", OpQuery, Workflow("BillingFlow"), false},
+		{[]string{"billing-tenant"}, "billing", OpStart, Workflow("BillingFlow"), false},
+	}
+	for _, tt := range tests {
+		if got := p.allows(tt.roles, tt.tenant, tt.op, tt.res); got != tt.want {
+			t.Errorf("allows(%v, %q, %s, %s/%s) = %v, want %v", tt.roles, tt.tenant, tt.op, tt.res.Kind, tt.res.Name, got, tt.want)
+		}
+	}
+}
+
+func TestNewPolicyRejectsInvalidGrants(t *testing.T) {
+	for name, grant := range map[string]Grant{
+		"operation":          {Operations: []string{"delete"}, Workflows: []string{"*"}},
+		"workflow pattern":   {Operations: []string{"query"}, Workflows: []string{"[Billing"}},
+		"definition pattern": {Operations: []string{"define"}, Definitions: []string{"Billing["}},
+		"tenant pattern":     {Operations: []string{"query"}, Workflows: []string{"*"}, Tenants: []string{"[a-"}},
+	} {
+		if _, err := newPolicy(map[string][]Grant{"r": {grant}}); err == nil {
+			t.Errorf("%s: invalid grant accepted", name)
+		}
+	}
+}
+
+func TestAuthorizeAndSelectTenant(t *testing.T) {
+	p := testPolicy(t)
+	ctx := WithPrincipal(context.Background(), &Principal{Method: "apikey", Subject: "ci", Roles: []string{"viewer", "billing-tenant"}, policy: p})
+
+	if err := Authorize(ctx, OpQuery, Workflow("BillingFlow")); err != nil {
+		t.Errorf("default namespace: %v", err)
+	}
+	if _, err := SelectTenant(ctx, "shipping"); !isForbidden(err, "tenant/shipping") {
+		t.Errorf("SelectTenant(shipping) = %v, want forbidden", err)
+	}
+	tctx, err := SelectTenant(ctx, "billing")
+	if err != nil {
+		t.Fatalf("SelectTenant(billing) = %v", err)
+	}
+	if err := Authorize(tctx, OpQuery, Workflow("BillingFlow")); err != nil {
+		t.Errorf("tenant billing: %v", err)
+	}
+	if err := Authorize(tctx, OpSignal, Workflow("BillingFlow")); !isForbidden(err, "signal:workflow/BillingFlow") {
+		t.Errorf("tenant billing signal = %v, want forbidden", err)
+	}
+	if FromContext(ctx).Tenant != "" {
+		t.Error("SelectTenant modified the original principal")
+	}
+
+	// 未启用认证或未配置 roles 时不检查
+	open := WithPrincipal(context.Background(), &Principal{Method: "apikey", Subject: "ci"})
+	for _, c := range []context.Context{context.Background(), open} {
+		if _, err := SelectTenant(c, "shipping"); err != nil {
+			t.Errorf("SelectTenant without policy = %v", err)
+		}
+		if err := Authorize(c, OpTerminate, Workflow("BillingFlow")); err != nil {
+			t.Errorf("Authorize without policy = %v", err)
+		}
+	}
+}
+
+func isForbidden(err error, permission string) bool {
+	var forbidden *ForbiddenError
+	return errors.As(err, &forbidden) && forbidden.Permission == permission
+}
@@ -0,0 +1,104 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// EncodingBlobRef 引用 payload 的 encoding，原 payload（含 metadata）序列化后保存在 blob store
+	EncodingBlobRef = "binary/blob-ref"
+	// MetadataBlobKey blob 的 key
+	MetadataBlobKey = "blob-key"
+	// MetadataBlobSize 原 payload 序列化后的大小，便于在 UI / history 中判断
+	MetadataBlobSize = "blob-size"
+	// PayloadPrefix 转存 payload 的 key 前缀，GC 在引用的 workflow 关闭并超过 retention 后清理
+	PayloadPrefix = "payloads/"
+
+	// ioTimeout PayloadCodec 接口不带 context，单次读写 blob 的超时
+	ioTimeout = 30 * time.Second
+)
+
+// Codec 实现 converter.PayloadCodec：序列化后超过 threshold 的 payload 写入 store，替换为引用。
+// key 为内容的 sha256，相同内容只保存一份，重复写入会刷新修改时间；知道所属 workflow 时（见 DataConverter）
+// 同时写入引用，GC 在引用的 workflow 都过期前不会删除。
+type Codec struct {
+	store     Store
+	threshold int
+	owner     Owner
+}
+
+var _ converter.PayloadCodec = (*Codec)(nil)
+
+// NewCodec 创建 codec，threshold <= 0 时使用 128KiB
+func NewCodec(store Store, threshold int) *Codec {
+	if threshold <= 0 {
+		threshold = 128 << 10
+	}
+	return &Codec{store: store, threshold: threshold}
+}
+
+func (c *Codec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		if proto.Size(p) <= c.threshold {
+			result[i] = p
+			continue
+		}
+		data, err := proto.Marshal(p)
+		if err != nil {
+			return payloads, fmt.Errorf("blobstore: marshal payload: %w", err)
+		}
+		sum := sha256.Sum256(data)
+		key := PayloadPrefix + hex.EncodeToString(sum[:])
+		ctx, cancel := context.WithTimeout(context.Background(), ioTimeout)
+		err = c.store.Put(ctx, key, data)
+		if err == nil && c.owner.WorkflowID != "" {
+			err = c.store.Put(ctx, refKey(key, c.owner), nil)
+		}
+		cancel()
+		if err != nil {
+			return payloads, fmt.Errorf("blobstore: put %s: %w", key, err)
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				converter.MetadataEncoding: []byte(EncodingBlobRef),
+				MetadataBlobKey:            []byte(key),
+				MetadataBlobSize:           []byte(strconv.Itoa(len(data))),
+			},
+		}
+	}
+	return result, nil
+}
+
+// Decode 读取引用 payload 对应的 blob，其他 payload 原样返回
+func (c *Codec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		if string(p.GetMetadata()[converter.MetadataEncoding]) != EncodingBlobRef {
+			result[i] = p
+			continue
+		}
+		key := string(p.GetMetadata()[MetadataBlobKey])
+		ctx, cancel := context.WithTimeout(context.Background(), ioTimeout)
+		data, err := c.store.Get(ctx, key)
+		cancel()
+		if err != nil {
+			return payloads, fmt.Errorf("blobstore: get %s: %w", key, err)
+		}
+		decoded := &commonpb.Payload{}
+		if err := proto.Unmarshal(data, decoded); err != nil {
+			return payloads, fmt.Errorf("blobstore: unmarshal %s: %w", key, err)
+		}
+		result[i] = decoded
+	}
+	return result, nil
+}
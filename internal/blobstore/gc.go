@@ -0,0 +1,135 @@
+package blobstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// GCRule GC 清理的一类 blob：key 以 Prefix 开头且修改时间早于 now - MaxAge。
+// Closed 不为 nil 时还检查 RefPrefix 下记录的引用：只有引用的 workflow 都已关闭超过 MaxAge 才删除，
+// 没有引用记录的 blob（如 schedule 的 input）只按修改时间
+type GCRule struct {
+	Prefix string
+	MaxAge time.Duration
+	Closed ClosedFunc
+}
+
+// ClosedFunc 返回 workflow 是否已关闭及关闭时间；workflow 不存在（已被 retention 清理）时返回零值时间和 true
+type ClosedFunc func(ctx context.Context, o Owner) (closeTime time.Time, closed bool, err error)
+
+type blobRef struct {
+	key   string
+	owner Owner
+}
+
+// GC 按 rules 删除过期的 blob 及其引用，返回删除的 blob 数量；不匹配任何规则的 blob（如邮件附件）不会删除
+func GC(ctx context.Context, store Store, rules []GCRule) (int, error) {
+	now := time.Now()
+	var expired []string
+	matched := map[string]*GCRule{}
+	refs := map[string][]blobRef{}
+	err := store.Walk(ctx, func(key string, modTime time.Time) error {
+		if strings.HasPrefix(key, RefPrefix) {
+			blobKey, o, err := parseRefKey(key)
+			if err != nil {
+				logger.Sugar.Warnw("blob gc: skipping invalid ref", "key", key, "err", err)
+				return nil
+			}
+			refs[blobKey] = append(refs[blobKey], blobRef{key: key, owner: o})
+			return nil
+		}
+		for i := range rules {
+			r := &rules[i]
+			if strings.HasPrefix(key, r.Prefix) {
+				matched[key] = r
+				if modTime.Before(now.Add(-r.MaxAge)) {
+					expired = append(expired, key)
+				}
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// 同一 workflow 可能引用多个 blob，每轮只查询一次
+	closed := map[Owner]bool{}
+	ownerExpired := func(r *GCRule, o Owner) bool {
+		if v, ok := closed[o]; ok {
+			return v
+		}
+		closeTime, ok, err := r.Closed(ctx, o)
+		if err != nil {
+			logger.Sugar.Warnw("blob gc: unable to determine workflow state, keeping its blobs",
+				"namespace", o.Namespace, "workflowId", o.WorkflowID, "err", err)
+		}
+		v := err == nil && ok && closeTime.Before(now.Add(-r.MaxAge))
+		closed[o] = v
+		return v
+	}
+
+	deleted := 0
+	for _, key := range expired {
+		r := matched[key]
+		keep := false
+		if r.Closed != nil {
+			for _, ref := range refs[key] {
+				if !ownerExpired(r, ref.owner) {
+					keep = true
+					break
+				}
+			}
+		}
+		if keep {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			return deleted, err
+		}
+		deleted++
+		for _, ref := range refs[key] {
+			if err := store.Delete(ctx, ref.key); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	// blob 已不存在的引用（blob 先于引用写入，说明已被删除）
+	for blobKey, rs := range refs {
+		if _, ok := matched[blobKey]; ok {
+			continue
+		}
+		for _, ref := range rs {
+			if err := store.Delete(ctx, ref.key); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// RunGC 每隔 interval 清理一次，直到 ctx 结束。rules 每次调用时计算（namespace retention 可能被修改），
+// 出错时跳过本轮
+func RunGC(ctx context.Context, store Store, interval time.Duration, rules func(context.Context) ([]GCRule, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		rs, err := rules(ctx)
+		if err != nil {
+			logger.Sugar.Warnw("blob gc skipped: unable to determine retention", "err", err)
+		} else if n, err := GC(ctx, store, rs); err != nil {
+			logger.Sugar.Errorw("blob gc failed", "deleted", n, "err", err)
+		} else if n > 0 {
+			logger.Sugar.Infow("blob gc finished", "deleted", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	logger "zebra-workflow/internal/log"
+)
+
+func TestMain(m *testing.M) {
+	if err := logger.Init("error", "json", []string{"stderr"}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func TestGCOnlyDeletesExpiredKeysUnderRules(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFSStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	old := time.Now().Add(-48 * time.Hour)
+	blobs := map[string]time.Time{
+		PayloadPrefix + "old":    old,
+		PayloadPrefix + "new":    time.Now(),
+		SentMarkerPrefix + "old": old,
+		"attachments/report.pdf": old,
+	}
+	for key, mtime := range blobs {
+		if err := store.Put(ctx, key, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filepath.Join(dir, filepath.FromSlash(key)), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := GC(ctx, store, []GCRule{
+		{Prefix: PayloadPrefix, MaxAge: 24 * time.Hour},
+		{Prefix: SentMarkerPrefix, MaxAge: 72 * time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("deleted %d blobs, want 1", n)
+	}
+	for key := range blobs {
+		_, err := store.Get(ctx, key)
+		if deleted := errors.Is(err, ErrNotFound); deleted != (key == PayloadPrefix+"old") {
+			t.Errorf("%s: deleted = %v", key, deleted)
+		}
+	}
+}
+
+func TestGCKeepsPayloadsUntilOwnersExpire(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFSStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	running := Owner{Namespace: "default", WorkflowID: "long/running"}
+	recent := Owner{Namespace: "default", WorkflowID: "closed-recently"}
+	expired := Owner{Namespace: "billing", WorkflowID: "closed-long-ago"}
+	unknown := Owner{Namespace: "removed", WorkflowID: "wf"}
+	old := time.Now().Add(-48 * time.Hour)
+
+	blobs := map[string][]Owner{
+		PayloadPrefix + "running": {running},
+		PayloadPrefix + "recent":  {expired, recent},
+		PayloadPrefix + "expired": {expired},
+		PayloadPrefix + "unknown": {unknown},
+		PayloadPrefix + "noref":   nil,
+	}
+	put := func(key string) {
+		if err := store.Put(ctx, key, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filepath.Join(dir, filepath.FromSlash(key)), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key, owners := range blobs {
+		put(key)
+		for _, o := range owners {
+			put(refKey(key, o))
+		}
+	}
+	orphan := refKey(PayloadPrefix+"gone", expired)
+	put(orphan)
+
+	closed := func(_ context.Context, o Owner) (time.Time, bool, error) {
+		switch o {
+		case running:
+			return time.Time{}, false, nil
+		case recent:
+			return time.Now().Add(-time.Hour), true, nil
+		case expired:
+			return old, true, nil
+		}
+		return time.Time{}, false, errors.New("namespace is not configured")
+	}
+	n, err := GC(ctx, store, []GCRule{{Prefix: PayloadPrefix, MaxAge: 24 * time.Hour, Closed: closed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("deleted %d blobs, want 2", n)
+	}
+	for key, owners := range blobs {
+		want := key == PayloadPrefix+"expired" || key == PayloadPrefix+"noref"
+		_, err := store.Get(ctx, key)
+		if deleted := errors.Is(err, ErrNotFound); deleted != want {
+			t.Errorf("%s: deleted = %v, want %v", key, deleted, want)
+		}
+		for _, o := range owners {
+			_, err := store.Get(ctx, refKey(key, o))
+			if deleted := errors.Is(err, ErrNotFound); deleted != want {
+				t.Errorf("ref %s -> %v: deleted = %v, want %v", key, o, deleted, want)
+			}
+		}
+	}
+	if _, err := store.Get(ctx, orphan); !errors.Is(err, ErrNotFound) {
+		t.Errorf("orphan ref not deleted: %v", err)
+	}
+}
+
+func TestDataConverterRecordsOwner(t *testing.T) {
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc := NewDataConverter(NewCodec(store, 16))
+	owner := Owner{Namespace: "default", WorkflowID: "wf-1"}
+	big := strings.Repeat("x", 64)
+
+	p, err := dc.WithContext(WithOwner(context.Background(), owner)).ToPayload(big)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := string(p.Metadata[MetadataBlobKey])
+	if _, err := store.Get(context.Background(), refKey(key, owner)); err != nil {
+		t.Errorf("owner ref not written: %v", err)
+	}
+	var got string
+	if err := dc.FromPayload(p, &got); err != nil || got != big {
+		t.Errorf("FromPayload = %q, %v", got, err)
+	}
+
+	// 不知道所属 workflow 时只写 blob
+	if _, err := dc.WithContext(context.Background()).ToPayload(big + "y"); err != nil {
+		t.Fatal(err)
+	}
+	var refs int
+	_ = store.Walk(context.Background(), func(k string, _ time.Time) error {
+		if strings.HasPrefix(k, RefPrefix) {
+			refs++
+		}
+		return nil
+	})
+	if refs != 1 {
+		t.Errorf("%d refs written, want 1", refs)
+	}
+}
@@ -0,0 +1,105 @@
+package blobstore
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// RefPrefix 转存 payload 的引用索引：RefPrefix + <blob key>/<namespace>/<workflowID>（后两段 base64url 编码），内容为空。
+// GC 据此在引用的 workflow 都关闭且超过保留期后才删除 blob
+const RefPrefix = "blob-refs/"
+
+// Owner 写入 payload 的 workflow
+type Owner struct {
+	Namespace  string
+	WorkflowID string
+}
+
+type ownerKey struct{}
+
+// WithOwner 指定 context 中编码的 payload 所属的 workflow（如 client 启动 workflow、发送 signal 时）
+func WithOwner(ctx context.Context, o Owner) context.Context {
+	return context.WithValue(ctx, ownerKey{}, o)
+}
+
+// ownerFromContext 依次取 WithOwner 指定的 workflow 和活动所属的 workflow
+func ownerFromContext(ctx context.Context) (Owner, bool) {
+	if o, ok := ctx.Value(ownerKey{}).(Owner); ok {
+		return o, o.WorkflowID != ""
+	}
+	if activity.IsActivity(ctx) {
+		info := activity.GetInfo(ctx)
+		return Owner{Namespace: info.WorkflowNamespace, WorkflowID: info.WorkflowExecution.ID}, info.WorkflowExecution.ID != ""
+	}
+	return Owner{}, false
+}
+
+func refKey(blobKey string, o Owner) string {
+	enc := base64.RawURLEncoding
+	return RefPrefix + blobKey + "/" + enc.EncodeToString([]byte(o.Namespace)) + "/" + enc.EncodeToString([]byte(o.WorkflowID))
+}
+
+// parseRefKey 解析 refKey 生成的 key
+func parseRefKey(key string) (blobKey string, o Owner, err error) {
+	parts := strings.Split(strings.TrimPrefix(key, RefPrefix), "/")
+	if len(parts) < 3 {
+		return "", Owner{}, fmt.Errorf("blobstore: invalid ref key %q", key)
+	}
+	enc := base64.RawURLEncoding
+	ns, err := enc.DecodeString(parts[len(parts)-2])
+	if err != nil {
+		return "", Owner{}, fmt.Errorf("blobstore: invalid ref key %q: %w", key, err)
+	}
+	wid, err := enc.DecodeString(parts[len(parts)-1])
+	if err != nil {
+		return "", Owner{}, fmt.Errorf("blobstore: invalid ref key %q: %w", key, err)
+	}
+	return strings.Join(parts[:len(parts)-2], "/"), Owner{Namespace: string(ns), WorkflowID: string(wid)}, nil
+}
+
+// DataConverter 使用 codecs 编解码 payload。workflow / 活动 / WithOwner 的 context 中编码时，
+// 转存的 payload 额外记录所属的 workflow（见 RefPrefix）
+type DataConverter struct {
+	converter.DataConverter
+	codecs []converter.PayloadCodec
+}
+
+var _ workflow.ContextAware = (*DataConverter)(nil)
+
+// NewDataConverter 在默认 data converter 之上按 codecs 的顺序编解码（同 converter.NewCodecDataConverter）
+func NewDataConverter(codecs ...converter.PayloadCodec) *DataConverter {
+	return &DataConverter{
+		DataConverter: converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codecs...),
+		codecs:        codecs,
+	}
+}
+
+func (d *DataConverter) WithWorkflowContext(ctx workflow.Context) converter.DataConverter {
+	info := workflow.GetInfo(ctx)
+	return d.withOwner(Owner{Namespace: info.Namespace, WorkflowID: info.WorkflowExecution.ID})
+}
+
+func (d *DataConverter) WithContext(ctx context.Context) converter.DataConverter {
+	o, ok := ownerFromContext(ctx)
+	if !ok {
+		return d
+	}
+	return d.withOwner(o)
+}
+
+func (d *DataConverter) withOwner(o Owner) converter.DataConverter {
+	codecs := make([]converter.PayloadCodec, len(d.codecs))
+	for i, c := range d.codecs {
+		if bc, ok := c.(*Codec); ok {
+			c = &Codec{store: bc.store, threshold: bc.threshold, owner: o}
+		}
+		codecs[i] = c
+	}
+	return converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codecs...)
+}
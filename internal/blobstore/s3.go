@@ -0,0 +1,104 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config S3 兼容存储（AWS S3、MinIO 等），凭据从环境变量读取，不写入配置文件
+type S3Config struct {
+	// Endpoint 如 "s3.amazonaws.com" 或 "127.0.0.1:9000"（MinIO）
+	Endpoint string `json:"endpoint,optional" yaml:"endpoint"`
+	Bucket   string `json:"bucket,optional" yaml:"bucket"`
+	Region   string `json:"region,optional" yaml:"region"`
+	// Prefix 对象 key 的前缀，如 "zebra/"
+	Prefix string `json:"prefix,optional" yaml:"prefix"`
+	UseSSL bool   `json:"useSSL,optional" yaml:"useSSL"`
+	// AccessKeyEnv / SecretKeyEnv 为空时依次尝试 AWS_* 环境变量、~/.aws/credentials 和实例角色
+	AccessKeyEnv string `json:"accessKeyEnv,optional" yaml:"accessKeyEnv"`
+	SecretKeyEnv string `json:"secretKeyEnv,optional" yaml:"secretKeyEnv"`
+}
+
+// S3Store S3 兼容存储后端
+type S3Store struct {
+	cli    *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store 创建 S3 后端，bucket 必须已存在
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("blobstore: s3 endpoint and bucket are required")
+	}
+	var creds *credentials.Credentials
+	if cfg.AccessKeyEnv != "" || cfg.SecretKeyEnv != "" {
+		accessKey, secretKey := os.Getenv(cfg.AccessKeyEnv), os.Getenv(cfg.SecretKeyEnv)
+		if accessKey == "" || secretKey == "" {
+			return nil, fmt.Errorf("blobstore: environment variables %s / %s are empty", cfg.AccessKeyEnv, cfg.SecretKeyEnv)
+		}
+		creds = credentials.NewStaticV4(accessKey, secretKey, "")
+	} else {
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.FileAWSCredentials{},
+			&credentials.IAM{},
+		})
+	}
+	cli, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: %w", err)
+	}
+	return &S3Store{cli: cli, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.cli.PutObject(ctx, s.bucket, s.prefix+key, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	return err
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	obj, err := s.cli.GetObject(ctx, s.bucket, s.prefix+key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(obj)
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return data, err
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	return s.cli.RemoveObject(ctx, s.bucket, s.prefix+key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3Store) Walk(ctx context.Context, fn func(key string, modTime time.Time) error) error {
+	// 提前返回时取消 ctx，结束 ListObjects 的后台 goroutine
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for obj := range s.cli.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return obj.Err
+		}
+		if err := fn(strings.TrimPrefix(obj.Key, s.prefix), obj.LastModified); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Package blobstore 把超过阈值的 Temporal payload 移到外部存储（本地文件系统或 S3 兼容存储），
+// history 中只保留引用；引用的 workflow 关闭并过了 retention 的 blob 由 worker 定期清理。
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound blob 不存在（可能已被 GC 清理）
+var ErrNotFound = errors.New("blob not found")
+
+// SentMarkerPrefix SendEmail 已发送标记（幂等键）的 key 前缀，GC 按 sentMarkerRetention 清理。
+// 其他前缀的 blob（如邮件附件）由写入方管理，GC 不会删除
+const SentMarkerPrefix = "email-sent/"
+
+// ProgressPrefix 活动重试时读回的中间结果（如 ExtractHTML 已完成页面的结果）的 key 前缀，GC 与 payload 相同
+const ProgressPrefix = "activity-progress/"
+
+// Store blob 存储后端
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	// Walk 遍历所有 blob，fn 返回错误时停止
+	Walk(ctx context.Context, fn func(key string, modTime time.Time) error) error
+}
+
+// Config 映射 temporal.blobStore
+type Config struct {
+	Enabled bool `json:"enabled,optional" yaml:"enabled"`
+	// Backend fs（默认）或 s3
+	Backend string `json:"backend,optional" yaml:"backend"`
+	// Threshold 超过该大小（字节，序列化后）的 payload 存入 blob store，默认 128KiB（Temporal 单个 payload 上限 2MB）
+	Threshold int `json:"threshold,optional" yaml:"threshold"`
+	// Dir fs 后端的根目录
+	Dir string   `json:"dir,optional" yaml:"dir"`
+	S3  S3Config `json:"s3,optional" yaml:"s3"`
+	// Retention blob 的保留时间，为空时使用 namespace 的 workflow retention（多个 namespace 取最大值）
+	Retention string `json:"retention,optional" yaml:"retention"`
+	// GracePeriod 在 retention 之外额外保留的时间，默认 168h。payload 在引用的 workflow 都关闭后按关闭时间计算保留期，
+	// 没有引用记录的 payload 按最后一次写入的时间计算
+	GracePeriod string `json:"gracePeriod,optional" yaml:"gracePeriod"`
+	// SentMarkerRetention SendEmail 已发送标记的保留时间，默认 720h；应大于 SendEmail 活动的最长重试时间
+	SentMarkerRetention string `json:"sentMarkerRetention,optional" yaml:"sentMarkerRetention"`
+	// GCInterval 清理间隔，默认 1h
+	GCInterval string `json:"gcInterval,optional" yaml:"gcInterval"`
+}
+
+// GCSettings 解析 GC 相关的时长；retention 为 0 表示使用 namespace 的 retention
+func (c Config) GCSettings() (retention, grace, interval time.Duration, err error) {
+	if retention, err = parseDuration("retention", c.Retention, 0); err != nil {
+		return
+	}
+	if grace, err = parseDuration("gracePeriod", c.GracePeriod, 7*24*time.Hour); err != nil {
+		return
+	}
+	interval, err = parseDuration("gcInterval", c.GCInterval, time.Hour)
+	return
+}
+
+// SentMarkerMaxAge 解析 sentMarkerRetention
+func (c Config) SentMarkerMaxAge() (time.Duration, error) {
+	return parseDuration("sentMarkerRetention", c.SentMarkerRetention, 30*24*time.Hour)
+}
+
+func parseDuration(name, s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("blobstore: invalid %s %q: %w", name, s, err)
+	}
+	return d, nil
+}
+
+// New 按配置创建存储后端；未启用时返回 nil
+func New(cfg Config) (Store, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	switch cfg.Backend {
+	case "", "fs":
+		return NewFSStore(cfg.Dir)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("blobstore: unknown backend %q", cfg.Backend)
+	}
+}
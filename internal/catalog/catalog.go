@@ -0,0 +1,148 @@
+// Package catalog 活动目录：登记每个活动的名称、说明、版本、输入 / 输出 JSON Schema 和错误类型。
+// worker 按目录注册活动，DSL 静态检查和 GET /v1/activities 读取目录。
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.temporal.io/sdk/activity"
+)
+
+// ErrorType 活动可能返回的 ApplicationError 类型
+type ErrorType struct {
+	Type        string `json:"type"`
+	Retryable   bool   `json:"retryable"`
+	Description string `json:"description,omitempty"`
+}
+
+// Activity 目录中的一个活动
+type Activity struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+	// InputSchema 活动参数（DSL Arguments 的参数名 -> 值）的 JSON Schema
+	InputSchema  json.RawMessage `json:"inputSchema,omitempty"`
+	OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
+	Errors       []ErrorType     `json:"errors,omitempty"`
+	// Deprecated 不为空时说明替代方式
+	Deprecated string `json:"deprecated,omitempty"`
+	// Heartbeat 活动运行期间定期心跳（AsyncActivity 由外部系统心跳），DSL 调用可以设置 HeartbeatTimeout
+	Heartbeat bool `json:"heartbeat,omitempty"`
+
+	// Func 活动实现；依赖 worker 配置的活动（如 SendEmail）为空，由 worker 注册时提供
+	Func interface{} `json:"-"`
+}
+
+var (
+	mu         sync.RWMutex
+	activities = make(map[string]Activity)
+)
+
+// Register 登记活动，通常在定义活动的包的 init 中调用；名称为空、重名或 schema 不是合法 JSON 时 panic
+func Register(a Activity) {
+	if a.Name == "" {
+		panic("catalog: activity without name")
+	}
+	for _, s := range []json.RawMessage{a.InputSchema, a.OutputSchema} {
+		if len(s) > 0 && !json.Valid(s) {
+			panic("catalog: invalid schema for activity " + a.Name)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := activities[a.Name]; ok {
+		panic("catalog: duplicate activity " + a.Name)
+	}
+	activities[a.Name] = a
+}
+
+// Lookup 按名称查找活动
+func Lookup(name string) (Activity, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	a, ok := activities[name]
+	return a, ok
+}
+
+// List 返回全部活动，按名称排序
+func List() []Activity {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]Activity, 0, len(activities))
+	for _, a := range activities {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Registry worker.Worker 中注册活动的部分
+type Registry interface {
+	RegisterActivityWithOptions(a interface{}, options activity.RegisterOptions)
+}
+
+// RegisterWorker 在 w 上以目录中的名称注册全部活动。impls 为 Func 为空的活动提供实现（活动名 -> 函数），
+// 目录中的活动没有实现或 impls 中有目录外的名称时返回错误，不注册任何活动
+func RegisterWorker(w Registry, impls map[string]interface{}) error {
+	list := List()
+	fns := make([]interface{}, len(list))
+	for i, a := range list {
+		fns[i] = a.Func
+		if impl, ok := impls[a.Name]; ok {
+			fns[i] = impl
+		}
+		if fns[i] == nil {
+			return fmt.Errorf("catalog: no implementation for activity %s", a.Name)
+		}
+	}
+	for name := range impls {
+		if _, ok := Lookup(name); !ok {
+			return fmt.Errorf("catalog: activity %s is not in the catalog", name)
+		}
+	}
+	for i, a := range list {
+		w.RegisterActivityWithOptions(fns[i], activity.RegisterOptions{Name: a.Name})
+	}
+	return nil
+}
+
+// Params InputSchema 中与参数名有关的部分
+type Params struct {
+	Properties map[string]bool
+	Required   []string
+	// AnyOf anyOf 中每个分支的 required，至少满足一个分支
+	AnyOf [][]string
+	// Closed additionalProperties 为 false，只接受 Properties 中的参数
+	Closed bool
+}
+
+// Parameters 解析 InputSchema 顶层的 properties / required / anyOf / additionalProperties，供 DSL 静态检查参数名；
+// 没有 schema 时返回 false
+func (a Activity) Parameters() (Params, bool) {
+	var s struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+		AnyOf      []struct {
+			Required []string `json:"required"`
+		} `json:"anyOf"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	if len(a.InputSchema) == 0 || json.Unmarshal(a.InputSchema, &s) != nil {
+		return Params{}, false
+	}
+	p := Params{
+		Properties: make(map[string]bool, len(s.Properties)),
+		Required:   s.Required,
+		Closed:     string(s.AdditionalProperties) == "false",
+	}
+	for k := range s.Properties {
+		p.Properties[k] = true
+	}
+	for _, b := range s.AnyOf {
+		p.AnyOf = append(p.AnyOf, b.Required)
+	}
+	return p, true
+}
@@ -0,0 +1,193 @@
+// Package codec 提供 Temporal payload 加密：AES-256-GCM，密钥来自本地 keyring 文件。
+// server 和 worker 的 client 共享同一个 DataConverter，Temporal server 和 UI 只能看到密文；
+// UI 通过 HTTP server 的 /codec/decode 解密展示。
+package codec
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// EncodingEncrypted 加密后 payload 的 encoding，解密时只处理带该 encoding 的 payload
+	EncodingEncrypted = "binary/encrypted"
+	// MetadataKeyID 加密所用的 key ID，轮换密钥后旧 payload 仍可用旧 key 解密
+	MetadataKeyID = "encryption-key-id"
+)
+
+// Config 映射 temporal.codec
+type Config struct {
+	Enabled bool `json:"enabled,optional" yaml:"enabled"`
+	// KeyringFile JSON 文件：{"current": "<keyId>", "keys": {"<keyId>": "<base64 32 字节>"}}
+	KeyringFile string `json:"keyringFile,optional" yaml:"keyringFile"`
+	// AllowedOrigins 允许跨域调用 /codec/* 的来源（Temporal UI 地址），为空时不返回 CORS 头；未启用加密、
+	// 只启用 blob 转存时同样生效；
+	// "*" 允许任意来源但不允许凭据，Temporal UI 开启 "Include cross-origin credentials" 时需列出具体地址
+	AllowedOrigins []string `json:"allowedOrigins,optional" yaml:"allowedOrigins"`
+}
+
+// keyringFile keyring 文件的格式
+type keyringFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// Keyring key ID -> AEAD，current 用于加密，所有 key 都可用于解密
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// LoadKeyring 读取 keyring 文件，每个 key 必须是 base64 编码的 32 字节（AES-256）
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("codec: read keyring: %w", err)
+	}
+	var f keyringFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("codec: parse keyring %s: %w", path, err)
+	}
+	if len(f.Keys) == 0 {
+		return nil, fmt.Errorf("codec: keyring %s has no keys", path)
+	}
+	if _, ok := f.Keys[f.Current]; !ok {
+		return nil, fmt.Errorf("codec: keyring %s: current key %q not found", path, f.Current)
+	}
+	ring := &Keyring{current: f.Current, keys: make(map[string]cipher.AEAD, len(f.Keys))}
+	for id, encoded := range f.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("codec: key %q: %w", id, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("codec: key %q must be 32 bytes, got %d", id, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("codec: key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("codec: key %q: %w", id, err)
+		}
+		ring.keys[id] = aead
+	}
+	return ring, nil
+}
+
+// Codec 实现 converter.PayloadCodec：把整个 payload（含原 metadata）序列化后用 AES-GCM 加密，
+// 密文格式为 nonce || ciphertext，key ID 写入 metadata
+type Codec struct {
+	ring *Keyring
+}
+
+var _ converter.PayloadCodec = (*Codec)(nil)
+
+// NewCodec 使用 keyring 创建 codec
+func NewCodec(ring *Keyring) *Codec {
+	return &Codec{ring: ring}
+}
+
+// New 按配置创建 codec；未启用时返回 nil
+func New(cfg Config) (*Codec, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if cfg.KeyringFile == "" {
+		return nil, errors.New("codec: keyringFile is required")
+	}
+	ring, err := LoadKeyring(cfg.KeyringFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewCodec(ring), nil
+}
+
+// Seal 用 current key 加密 plain，返回 key ID 和 nonce || ciphertext；aad 为附加认证数据（为空时使用 key ID）
+func (k *Keyring) Seal(plain, aad []byte) (keyID string, data []byte, err error) {
+	keyID = k.current
+	aead := k.keys[keyID]
+	if aad == nil {
+		aad = []byte(keyID)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, fmt.Errorf("codec: generate nonce: %w", err)
+	}
+	return keyID, aead.Seal(nonce, nonce, plain, aad), nil
+}
+
+// Open 用 keyID 对应的 key 解密 Seal 的结果
+func (k *Keyring) Open(keyID string, data, aad []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("codec: unknown encryption key %q", keyID)
+	}
+	if aad == nil {
+		aad = []byte(keyID)
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("codec: encrypted data too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("codec: decrypt with key %q: %w", keyID, err)
+	}
+	return plain, nil
+}
+
+// Encode 使用 current key 加密每个 payload
+func (c *Codec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		plain, err := proto.Marshal(p)
+		if err != nil {
+			return payloads, fmt.Errorf("codec: marshal payload: %w", err)
+		}
+		keyID, data, err := c.ring.Seal(plain, nil)
+		if err != nil {
+			return payloads, err
+		}
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				converter.MetadataEncoding: []byte(EncodingEncrypted),
+				MetadataKeyID:              []byte(keyID),
+			},
+			Data: data,
+		}
+	}
+	return result, nil
+}
+
+// Decode 解密带 EncodingEncrypted 的 payload，其他 payload 原样返回（兼容启用加密前的历史数据）
+func (c *Codec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		if string(p.GetMetadata()[converter.MetadataEncoding]) != EncodingEncrypted {
+			result[i] = p
+			continue
+		}
+		plain, err := c.ring.Open(string(p.GetMetadata()[MetadataKeyID]), p.GetData(), nil)
+		if err != nil {
+			return payloads, err
+		}
+		decoded := &commonpb.Payload{}
+		if err := proto.Unmarshal(plain, decoded); err != nil {
+			return payloads, fmt.Errorf("codec: unmarshal payload: %w", err)
+		}
+		result[i] = decoded
+	}
+	return result, nil
+}
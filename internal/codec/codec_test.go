@@ -0,0 +1,111 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+func writeKeyring(t *testing.T, current string, ids ...string) string {
+	t.Helper()
+	f := keyringFile{Current: current, Keys: map[string]string{}}
+	for i, id := range ids {
+		f.Keys[id] = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{byte(i + 1)}, 32))
+	}
+	data, _ := json.Marshal(f)
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCodecRoundTripAcrossRotation(t *testing.T) {
+	before, err := LoadKeyring(writeKeyring(t, "k1", "k1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := LoadKeyring(writeKeyring(t, "k2", "k1", "k2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := converter.GetDefaultDataConverter().ToPayload(map[string]string{"title": "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := &commonpb.Payload{Metadata: map[string][]byte{converter.MetadataEncoding: []byte("binary/plain")}, Data: []byte("legacy")}
+
+	old, err := NewCodec(before).Encode([]*commonpb.Payload{payload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated := NewCodec(after)
+	fresh, err := rotated.Encode([]*commonpb.Payload{payload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(fresh[0].Metadata[MetadataKeyID]); got != "k2" {
+		t.Errorf("encoded with key %q, want k2", got)
+	}
+	if bytes.Contains(fresh[0].Data, payload.Data) {
+		t.Error("encoded payload contains the plaintext")
+	}
+
+	decoded, err := rotated.Decode([]*commonpb.Payload{old[0], fresh[0], plain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []*commonpb.Payload{payload, payload, plain} {
+		if !proto.Equal(decoded[i], want) {
+			t.Errorf("payload %d: got %v, want %v", i, decoded[i], want)
+		}
+	}
+
+	tampered := proto.Clone(fresh[0]).(*commonpb.Payload)
+	tampered.Data[len(tampered.Data)-1] ^= 1
+	if _, err := rotated.Decode([]*commonpb.Payload{tampered}); err == nil {
+		t.Error("tampered payload decoded without error")
+	}
+	if _, err := NewCodec(before).Decode(fresh); err == nil {
+		t.Error("payload encrypted with an unknown key decoded without error")
+	}
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		allowed     []string
+		origin      string
+		wantOrigin  string
+		credentials bool
+	}{
+		{allowed: []string{"http://ui:8088"}, origin: "http://ui:8088", wantOrigin: "http://ui:8088", credentials: true},
+		{allowed: []string{"http://ui:8088"}, origin: "http://evil", wantOrigin: ""},
+		{allowed: []string{"*"}, origin: "http://evil", wantOrigin: "*"},
+		{allowed: []string{"*", "http://ui:8088"}, origin: "http://ui:8088", wantOrigin: "http://ui:8088", credentials: true},
+		{allowed: nil, origin: "http://ui:8088", wantOrigin: ""},
+	}
+	for _, tt := range tests {
+		for _, method := range []string{http.MethodOptions, http.MethodPost} {
+			req := httptest.NewRequest(method, "/codec/decode", nil)
+			req.Header.Set("Origin", tt.origin)
+			rec := httptest.NewRecorder()
+			CORS(tt.allowed, func(w http.ResponseWriter, r *http.Request) {})(rec, req)
+			h := rec.Header()
+			if got := h.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("%v %s %s: Allow-Origin = %q, want %q", tt.allowed, method, tt.origin, got, tt.wantOrigin)
+			}
+			if got := h.Get("Access-Control-Allow-Credentials") == "true"; got != tt.credentials {
+				t.Errorf("%v %s %s: Allow-Credentials = %v, want %v", tt.allowed, method, tt.origin, got, tt.credentials)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package codec
+
+import (
+	"net/http"
+)
+
+// CORS 为 codec server（/codec/*）的 allowedOrigins 中的来源返回 CORS 头并直接应答预检请求（预检不带凭据），
+// 放在认证之前，使 401 / 403 也能被浏览器中的 Temporal UI 读到。不依赖加密 codec，只启用 blob 转存时同样适用。
+// 明确列出的来源原样返回并允许凭据；只匹配 "*" 的来源返回字面的 "*"，不允许凭据（浏览器不会为其附带 cookie）
+func CORS(allowedOrigins []string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			h := w.Header()
+			switch originAllowed(allowedOrigins, origin) {
+			case originListed:
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Set("Access-Control-Allow-Credentials", "true")
+				h.Add("Vary", "Origin")
+			case originWildcard:
+				h.Set("Access-Control-Allow-Origin", "*")
+			}
+			if h.Get("Access-Control-Allow-Origin") != "" {
+				h.Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+				h.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-API-Key, X-Namespace")
+			}
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next(w, r)
+	}
+}
+
+type originMatch int
+
+const (
+	originDenied originMatch = iota
+	originListed
+	originWildcard
+)
+
+func originAllowed(allowedOrigins []string, origin string) originMatch {
+	match := originDenied
+	for _, o := range allowedOrigins {
+		switch o {
+		case origin:
+			return originListed
+		case "*":
+			match = originWildcard
+		}
+	}
+	return match
+}
@@ -0,0 +1,45 @@
+package definition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrNotFound 定义不存在
+var ErrNotFound = errors.New("definition not found")
+
+// 定义名只允许字母、数字、下划线、点和中划线，避免越出定义目录
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// Store 从目录读取保存好的 DSL 定义，每个定义对应一个 <name>.json 文件，内容与启动 DSLWorkflow 时的 input 相同
+type Store struct {
+	dir string
+}
+
+// NewStore 创建以 dir 为根目录的定义仓库
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Load 读取名为 name 的定义
+func (s *Store) Load(name string) (map[string]interface{}, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid definition name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, name+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var def map[string]interface{}
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("parse definition %s: %w", name, err)
+	}
+	return def, nil
+}
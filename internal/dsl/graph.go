@@ -0,0 +1,284 @@
+package dsl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 图节点的类型
+const (
+	NodeActivity  = "activity"
+	NodeSequence  = "sequence"
+	NodeParallel  = "parallel"
+	NodeStatement = "statement"
+	NodeVariable  = "variable"
+	NodeOutput    = "output"
+)
+
+// 图边的类型：control 边从语句指向其包含的语句，data 边从 binding 的来源（变量或活动的 Result）指向以其为参数的活动
+const (
+	EdgeControl = "control"
+	EdgeData    = "data"
+)
+
+type (
+	// Graph DSL workflow 的图结构，可渲染为 mermaid / dot
+	Graph struct {
+		Nodes []GraphNode `json:"nodes"`
+		Edges []GraphEdge `json:"edges"`
+	}
+
+	// GraphNode 图节点：语句节点的 ID 为语句路径，变量节点为 var:<变量名>
+	GraphNode struct {
+		ID    string `json:"id"`
+		Kind  string `json:"kind"`
+		Label string `json:"label"`
+		// Undefined 被引用但没有定义的变量
+		Undefined bool `json:"undefined,omitempty"`
+	}
+
+	// GraphEdge 图的边，Label 为 data 边的 binding 名称或并行分支的序号
+	GraphEdge struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Kind  string `json:"kind"`
+		Label string `json:"label,omitempty"`
+	}
+
+	graphBuilder struct {
+		g     *Graph
+		nodes map[string]bool
+	}
+)
+
+// ToGraph 把 DSL 转换为图：语句为节点（ID 为语句路径），包含关系为 control 边，
+// Result -> Arguments 的数据依赖为带 binding 名称的 data 边。
+func ToGraph(w Workflow) *Graph {
+	w.Root.assignIDs(RootStatementID)
+	b := &graphBuilder{g: &Graph{}, nodes: make(map[string]bool)}
+
+	producers := make(map[string]string, len(w.Variables))
+	for _, k := range sortedKeys(w.Variables) {
+		id := "var:" + k
+		b.node(GraphNode{ID: id, Kind: NodeVariable, Label: k})
+		producers[k] = id
+	}
+	b.statement(&w.Root, producers)
+	if w.Output != "" {
+		b.node(GraphNode{ID: "output", Kind: NodeOutput, Label: "output"})
+		b.data(producers, w.Output, "output")
+	}
+	return b.g
+}
+
+func (b *graphBuilder) node(n GraphNode) {
+	if b.nodes[n.ID] {
+		return
+	}
+	b.nodes[n.ID] = true
+	b.g.Nodes = append(b.g.Nodes, n)
+}
+
+func (b *graphBuilder) edge(e GraphEdge) {
+	b.g.Edges = append(b.g.Edges, e)
+}
+
+// data 添加 binding 的生产者到消费者的数据边，未定义的 binding 生成一个 Undefined 变量节点
+func (b *graphBuilder) data(producers map[string]string, binding, to string) {
+	from, ok := producers[binding]
+	if !ok {
+		from = "var:" + binding
+		b.node(GraphNode{ID: from, Kind: NodeVariable, Label: binding, Undefined: true})
+	}
+	b.edge(GraphEdge{From: from, To: to, Kind: EdgeData, Label: binding})
+}
+
+// statement 返回语句对应的节点 ID；同一语句同时包含多种类型时，生成一个 statement 节点依次连接各部分
+func (b *graphBuilder) statement(s *Statement, producers map[string]string) string {
+	var parts []string
+	if s.Parallel != nil {
+		parts = append(parts, NodeParallel)
+	}
+	if s.Sequence != nil {
+		parts = append(parts, NodeSequence)
+	}
+	if s.Activity != nil {
+		parts = append(parts, NodeActivity)
+	}
+	if len(parts) == 0 {
+		b.node(GraphNode{ID: s.id, Kind: NodeStatement, Label: s.id + "\n(empty)"})
+		return s.id
+	}
+	if len(parts) == 1 {
+		b.part(s, parts[0], s.id, producers)
+		return s.id
+	}
+	b.node(GraphNode{ID: s.id, Kind: NodeStatement, Label: s.id})
+	for _, kind := range parts {
+		id := s.id + "#" + kind
+		b.part(s, kind, id, producers)
+		b.edge(GraphEdge{From: s.id, To: id, Kind: EdgeControl})
+	}
+	return s.id
+}
+
+func (b *graphBuilder) part(s *Statement, kind, id string, producers map[string]string) {
+	switch kind {
+	case NodeParallel:
+		b.node(GraphNode{ID: id, Kind: NodeParallel, Label: s.id + "\nParallel"})
+		var produced map[string]string
+		for i, br := range s.Parallel.Branches {
+			if br == nil {
+				continue
+			}
+			// 分支只能看到并行块之前的 binding，结束后合并各分支的 Result
+			branch := make(map[string]string, len(producers))
+			for k, v := range producers {
+				branch[k] = v
+			}
+			child := b.statement(br, branch)
+			b.edge(GraphEdge{From: id, To: child, Kind: EdgeControl, Label: fmt.Sprintf("branch %d", i)})
+			for k, v := range branch {
+				if producers[k] != v {
+					if produced == nil {
+						produced = make(map[string]string)
+					}
+					produced[k] = v
+				}
+			}
+		}
+		for k, v := range produced {
+			producers[k] = v
+		}
+	case NodeSequence:
+		b.node(GraphNode{ID: id, Kind: NodeSequence, Label: s.id + "\nSequence"})
+		for i, e := range s.Sequence.Elements {
+			if e == nil {
+				continue
+			}
+			child := b.statement(e, producers)
+			b.edge(GraphEdge{From: id, To: child, Kind: EdgeControl, Label: fmt.Sprintf("step %d", i+1)})
+		}
+	case NodeActivity:
+		a := s.Activity
+		label := s.id + "\n" + a.Name
+		if a.Local {
+			label += " (local)"
+		}
+		b.node(GraphNode{ID: id, Kind: NodeActivity, Label: label})
+		for _, arg := range a.Arguments {
+			_, binding := splitArgument(arg)
+			b.data(producers, binding, id)
+		}
+		if a.Result != "" {
+			producers[a.Result] = id
+		}
+	}
+}
+
+// Mermaid 渲染为 Mermaid flowchart：control 边为虚线，data 边为带 binding 名称的实线
+func (g *Graph) Mermaid() string {
+	ids := g.shortIDs()
+	var sb strings.Builder
+	sb.WriteString("flowchart TD\n")
+	for _, n := range g.Nodes {
+		label := mermaidLabel(n.Label)
+		if n.Undefined {
+			label = mermaidLabel(n.Label + "\n(undefined)")
+		}
+		var shape string
+		switch n.Kind {
+		case NodeActivity:
+			shape = `["` + label + `"]`
+		case NodeSequence:
+			shape = `[["` + label + `"]]`
+		case NodeParallel:
+			shape = `{{"` + label + `"}}`
+		case NodeVariable:
+			shape = `(["` + label + `"])`
+		case NodeOutput:
+			shape = `(("` + label + `"))`
+		default:
+			shape = `("` + label + `")`
+		}
+		fmt.Fprintf(&sb, "    %s%s\n", ids[n.ID], shape)
+	}
+	for _, e := range g.Edges {
+		switch {
+		case e.Kind == EdgeData:
+			fmt.Fprintf(&sb, "    %s -->|\"%s\"| %s\n", ids[e.From], mermaidLabel(e.Label), ids[e.To])
+		case e.Label != "":
+			fmt.Fprintf(&sb, "    %s -.->|\"%s\"| %s\n", ids[e.From], mermaidLabel(e.Label), ids[e.To])
+		default:
+			fmt.Fprintf(&sb, "    %s -.-> %s\n", ids[e.From], ids[e.To])
+		}
+	}
+	return sb.String()
+}
+
+// DOT 渲染为 Graphviz DOT
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph dsl {\n    rankdir=TB;\n    node [fontname=\"Helvetica\"];\n")
+	for _, n := range g.Nodes {
+		attrs := "shape=box"
+		switch n.Kind {
+		case NodeActivity:
+			attrs = "shape=box, style=rounded"
+		case NodeSequence:
+			attrs = "shape=box, peripheries=2"
+		case NodeParallel:
+			attrs = "shape=hexagon"
+		case NodeVariable:
+			attrs = "shape=ellipse"
+			if n.Undefined {
+				attrs += ", color=red"
+			}
+		case NodeOutput:
+			attrs = "shape=doublecircle"
+		}
+		fmt.Fprintf(&sb, "    %s [label=%s, %s];\n", dotQuote(n.ID), dotQuote(n.Label), attrs)
+	}
+	for _, e := range g.Edges {
+		attrs := "style=dashed"
+		if e.Kind == EdgeData {
+			attrs = "style=solid"
+		}
+		if e.Label != "" {
+			attrs += ", label=" + dotQuote(e.Label)
+		}
+		fmt.Fprintf(&sb, "    %s -> %s [%s];\n", dotQuote(e.From), dotQuote(e.To), attrs)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// shortIDs 为 Mermaid 生成只含字母数字的节点 ID（语句路径含 / [ ] 等字符）
+func (g *Graph) shortIDs() map[string]string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+func mermaidLabel(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	return strings.ReplaceAll(s, "\n", "<br/>")
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
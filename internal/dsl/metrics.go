@@ -0,0 +1,39 @@
+package dsl
+
+import (
+	"go.temporal.io/sdk/workflow"
+)
+
+// DSL 指标通过 workflow.GetMetricsHandler 记录，重放时不会重复上报。
+// 使用 Prometheus 命名 scope 时，计数器带 _total 后缀，计时器带 _seconds 后缀。
+const (
+	metricStatement        = "dsl_statement"
+	metricStatementLatency = "dsl_statement_latency"
+	metricActivity         = "dsl_activity"
+	metricActivityLatency  = "dsl_activity_latency"
+)
+
+// measure 执行 fn 并按 tags 记录次数（附带 outcome）和耗时
+func measure(ctx workflow.Context, counter, timer string, tags map[string]string, fn func() error) error {
+	start := workflow.Now(ctx)
+	err := fn()
+
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	handler := workflow.GetMetricsHandler(ctx).WithTags(tags)
+	handler.WithTags(map[string]string{"outcome": outcome}).Counter(counter).Inc(1)
+	handler.Timer(timer).Record(workflow.Now(ctx).Sub(start))
+	return err
+}
+
+// measureStatement 按语句类型（activity / sequence / parallel）记录
+func measureStatement(ctx workflow.Context, statementType string, fn func() error) error {
+	return measure(ctx, metricStatement, metricStatementLatency, map[string]string{"statement_type": statementType}, fn)
+}
+
+// measureActivity 按活动名称记录
+func measureActivity(ctx workflow.Context, name string, fn func() error) error {
+	return measure(ctx, metricActivity, metricActivityLatency, map[string]string{"activity": name}, fn)
+}
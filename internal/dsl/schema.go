@@ -0,0 +1,62 @@
+package dsl
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"zebra-workflow/internal/schema"
+	"zebra-workflow/internal/secrets"
+)
+
+func (w *Workflow) UnmarshalJSON(data []byte) error {
+	type plain Workflow
+	if err := json.Unmarshal(data, (*plain)(w)); err != nil {
+		return err
+	}
+	var raw struct {
+		Variables map[string]json.RawMessage
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	w.rawVariables = raw.Variables
+	return nil
+}
+
+// CheckSchemas 按 VariablesSchema 检查 Variables，并确认 OutputSchema 可以编译（Output 的值在 workflow 结束时检查）。
+// Variables 按 JSON 中的原始类型检查，secret 引用按其文本形式（字符串）检查。
+// Variables 不符合时返回 *schema.ValidationError
+func (w Workflow) CheckSchemas() error {
+	if len(w.OutputSchema) > 0 {
+		if _, err := schema.Compile(w.OutputSchema); err != nil {
+			return fmt.Errorf("OutputSchema: %w", err)
+		}
+	}
+	if len(w.VariablesSchema) == 0 {
+		return nil
+	}
+	s, err := schema.Compile(w.VariablesSchema)
+	if err != nil {
+		return fmt.Errorf("VariablesSchema: %w", err)
+	}
+	return s.Validate("variables", w.variableValues())
+}
+
+// variableValues 返回 Variables 的原始 JSON 值；不是从 JSON 解析得到的 Workflow 使用 Variables 中的文本
+func (w Workflow) variableValues() map[string]interface{} {
+	values := make(map[string]interface{}, len(w.Variables))
+	for k, text := range w.Variables {
+		raw, ok := w.rawVariables[k]
+		if _, secret := secrets.ParseRef(text); secret || !ok {
+			values[k] = text
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			values[k] = text
+			continue
+		}
+		values[k] = v
+	}
+	return values
+}
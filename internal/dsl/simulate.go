@@ -0,0 +1,175 @@
+package dsl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+type (
+	// ActivityMock is a canned response for an activity during simulation. Call is the 1-based invocation number of
+	// that activity (retries count as new calls); 0 makes it the fallback for every call without an exact match.
+	ActivityMock struct {
+		Activity     string                 `json:"activity"`
+		Call         int                    `json:"call,omitempty"`
+		Output       map[string]interface{} `json:"output,omitempty"`
+		Error        string                 `json:"error,omitempty"`
+		NonRetryable bool                   `json:"nonRetryable,omitempty"`
+	}
+
+	// TraceEntry records one activity invocation observed during simulation.
+	TraceEntry struct {
+		Statement string                 `json:"statement"`
+		Activity  string                 `json:"activity"`
+		Call      int                    `json:"call"`
+		Input     map[string]string      `json:"input"`
+		Output    map[string]interface{} `json:"output,omitempty"`
+		Error     string                 `json:"error,omitempty"`
+	}
+
+	// SimulationResult is what a dry run of a DSL workflow produced. Error is set when the workflow itself failed.
+	SimulationResult struct {
+		Trace    []TraceEntry      `json:"trace"`
+		Bindings map[string]string `json:"bindings"`
+		Output   interface{}       `json:"output,omitempty"`
+		Error    string            `json:"error,omitempty"`
+	}
+
+	mockKey struct {
+		activity string
+		call     int
+	}
+
+	simulator struct {
+		mu    sync.Mutex
+		mocks map[mockKey]ActivityMock
+		calls map[string]int
+		trace []TraceEntry
+	}
+)
+
+// Simulate 在 Temporal 测试环境中离线执行 DSL，活动由 mocks 提供返回值，无需 Temporal server 和 worker。
+// variables 会覆盖 DSL 中的同名 Variables。
+func Simulate(w Workflow, variables map[string]string, mocks []ActivityMock) (*SimulationResult, error) {
+	merged := make(map[string]string, len(w.Variables)+len(variables))
+	for k, v := range w.Variables {
+		merged[k] = v
+	}
+	for k, v := range variables {
+		merged[k] = v
+	}
+	w.Variables = merged
+
+	sim := &simulator{
+		mocks: make(map[mockKey]ActivityMock, len(mocks)),
+		calls: make(map[string]int),
+	}
+	for _, m := range mocks {
+		if m.Activity == "" {
+			return nil, errors.New("activity mock without activity name")
+		}
+		sim.mocks[mockKey{activity: m.Activity, call: m.Call}] = m
+	}
+
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(SimpleDSLWorkflow)
+	for _, name := range activityNames(&w.Root) {
+		if name == "" {
+			return nil, errors.New("activity statement without name")
+		}
+		env.RegisterActivityWithOptions(sim.activity(name), activity.RegisterOptions{Name: name})
+	}
+
+	env.ExecuteWorkflow(SimpleDSLWorkflow, w)
+	if !env.IsWorkflowCompleted() {
+		return nil, errors.New("simulation did not complete")
+	}
+
+	res := &SimulationResult{Trace: sim.trace}
+	if err := env.GetWorkflowError(); err != nil {
+		res.Error = err.Error()
+	} else if err := env.GetWorkflowResult(&res.Output); err != nil {
+		return nil, fmt.Errorf("decode simulation output: %w", err)
+	}
+	if v, err := env.QueryWorkflow(BindingsQuery); err == nil {
+		if err := v.Get(&res.Bindings); err != nil {
+			return nil, fmt.Errorf("decode simulation bindings: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// activity 返回按名称注册到测试环境的模拟活动，按调用次数查找 mock 并记录 trace
+func (s *simulator) activity(name string) func(ctx context.Context, input map[string]string) (map[string]interface{}, error) {
+	return func(ctx context.Context, input map[string]string) (map[string]interface{}, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.calls[name]++
+		call := s.calls[name]
+		entry := TraceEntry{
+			Statement: activity.GetInfo(ctx).ActivityID,
+			Activity:  name,
+			Call:      call,
+			Input:     input,
+		}
+
+		m, ok := s.mocks[mockKey{activity: name, call: call}]
+		if !ok {
+			m, ok = s.mocks[mockKey{activity: name}]
+		}
+		var err error
+		switch {
+		case !ok:
+			err = temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("no mock for activity %s call %d", name, call), "SimulationMockMissing", nil)
+		case m.Error != "" && m.NonRetryable:
+			err = temporal.NewNonRetryableApplicationError(m.Error, "SimulatedError", nil)
+		case m.Error != "":
+			err = temporal.NewApplicationError(m.Error, "SimulatedError")
+		}
+		if err != nil {
+			entry.Error = err.Error()
+			s.trace = append(s.trace, entry)
+			return nil, err
+		}
+
+		entry.Output = m.Output
+		s.trace = append(s.trace, entry)
+		return m.Output, nil
+	}
+}
+
+// activityNames 收集 DSL 中引用到的全部活动名称（去重，按出现顺序）
+func activityNames(root *Statement) []string {
+	seen := make(map[string]bool)
+	var names []string
+	var walk func(s *Statement)
+	walk = func(s *Statement) {
+		if s == nil {
+			return
+		}
+		if s.Activity != nil && !seen[s.Activity.Name] {
+			seen[s.Activity.Name] = true
+			names = append(names, s.Activity.Name)
+		}
+		if s.Sequence != nil {
+			for _, e := range s.Sequence.Elements {
+				walk(e)
+			}
+		}
+		if s.Parallel != nil {
+			for _, b := range s.Parallel.Branches {
+				walk(b)
+			}
+		}
+	}
+	walk(root)
+	return names
+}
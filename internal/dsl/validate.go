@@ -0,0 +1,209 @@
+package dsl
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"zebra-workflow/internal/catalog"
+	"zebra-workflow/internal/schema"
+)
+
+type (
+	// Problem is a single issue found by Validate, located by statement path.
+	Problem struct {
+		Statement string `json:"statement"`
+		Message   string `json:"message"`
+	}
+
+	// ValidationError collects every Problem found in a DSL workflow.
+	ValidationError struct {
+		Problems []Problem
+	}
+)
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", p.Statement, p.Message))
+	}
+	return "invalid dsl workflow: " + strings.Join(msgs, "; ")
+}
+
+// Validate 静态检查 DSL：语句不能为空、活动必须有名称、Arguments 只能引用 Variables 或此前语句的 Result。
+// 并行分支之间的 Result 不保证先后，因此分支内不能引用兄弟分支的 Result。
+// 活动目录中有输入 schema 的活动还会检查必填参数和未知参数；目录外的活动（插件等）不检查参数。
+// 声明了 VariablesSchema 时按其检查 Variables（见 Workflow.CheckSchemas）。
+// 返回 nil 或 *ValidationError。
+func Validate(w Workflow) error {
+	w.Root.assignIDs(RootStatementID)
+
+	defined := make(map[string]bool, len(w.Variables))
+	for k := range w.Variables {
+		defined[k] = true
+	}
+	v := &validator{}
+	v.statement(&w.Root, defined)
+	if w.Output != "" && !defined[w.Output] {
+		v.add(RootStatementID, fmt.Sprintf("output %q is not a variable or a statement result", w.Output))
+	}
+	if err := w.CheckSchemas(); err != nil {
+		var verr *schema.ValidationError
+		if !errors.As(err, &verr) {
+			v.add(RootStatementID, err.Error())
+		} else {
+			for _, f := range verr.Errors {
+				v.add(RootStatementID, fmt.Sprintf("variables %s: %s", f.Field, f.Message))
+			}
+		}
+	}
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+type validator struct {
+	problems []Problem
+}
+
+func (v *validator) add(statement, msg string) {
+	v.problems = append(v.problems, Problem{Statement: statement, Message: msg})
+}
+
+// statement 按执行顺序检查语句，并把语句产生的 Result 加入 defined
+func (v *validator) statement(s *Statement, defined map[string]bool) {
+	if s.Activity == nil && s.Sequence == nil && s.Parallel == nil {
+		v.add(s.id, "statement has no Activity, Sequence or Parallel")
+		return
+	}
+	if s.Parallel != nil {
+		v.parallel(s, defined)
+	}
+	if s.Sequence != nil {
+		if len(s.Sequence.Elements) == 0 {
+			v.add(s.id, "sequence has no elements")
+		}
+		for i, e := range s.Sequence.Elements {
+			if e == nil {
+				v.add(fmt.Sprintf("%s/seq[%d]", s.id, i), "statement is null")
+				continue
+			}
+			v.statement(e, defined)
+		}
+	}
+	if s.Activity != nil {
+		a := s.Activity
+		if a.Name == "" {
+			v.add(s.id, "activity has no Name")
+		}
+		params := make(map[string]bool, len(a.Arguments))
+		for _, arg := range a.Arguments {
+			param, binding := splitArgument(arg)
+			if param == "" || binding == "" {
+				v.add(s.id, fmt.Sprintf("argument %q must be \"name\" or \"param=name\"", arg))
+				continue
+			}
+			params[param] = true
+			if !defined[binding] {
+				v.add(s.id, fmt.Sprintf("argument %q is not a variable or the result of an earlier statement", binding))
+			}
+		}
+		v.parameters(s.id, a.Name, params)
+		v.timeouts(s.id, a)
+		if a.Result != "" {
+			defined[a.Result] = true
+		}
+	}
+}
+
+// parameters 按活动目录中的输入 schema 检查参数名
+func (v *validator) parameters(id, activity string, params map[string]bool) {
+	entry, ok := catalog.Lookup(activity)
+	if !ok {
+		return
+	}
+	schema, ok := entry.Parameters()
+	if !ok {
+		return
+	}
+	for _, name := range schema.Required {
+		if !params[name] {
+			v.add(id, fmt.Sprintf("activity %s requires parameter %q", activity, name))
+		}
+	}
+	if len(schema.AnyOf) > 0 {
+		var alternatives []string
+		satisfied := false
+		for _, branch := range schema.AnyOf {
+			all := true
+			for _, name := range branch {
+				all = all && params[name]
+			}
+			satisfied = satisfied || all
+			alternatives = append(alternatives, strings.Join(branch, "+"))
+		}
+		if !satisfied {
+			v.add(id, fmt.Sprintf("activity %s requires one of the parameters %s", activity, strings.Join(alternatives, ", ")))
+		}
+	}
+	if !schema.Closed {
+		return
+	}
+	var unknown []string
+	for name := range params {
+		if !schema.Properties[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		v.add(id, fmt.Sprintf("activity %s has no parameter %q", activity, name))
+	}
+}
+
+// timeouts 检查调用自带的超时；HeartbeatTimeout 只能用于会心跳的活动，否则每次尝试都会超时
+func (v *validator) timeouts(id string, a *ActivityInvocation) {
+	t, err := a.timeouts()
+	if err != nil {
+		v.add(id, err.Error())
+		return
+	}
+	if t.heartbeat == 0 {
+		return
+	}
+	if entry, ok := catalog.Lookup(a.Name); ok && !entry.Heartbeat {
+		v.add(id, fmt.Sprintf("activity %s does not heartbeat, HeartbeatTimeout would fail every attempt", a.Name))
+	}
+	if t.startToClose > 0 && t.heartbeat >= t.startToClose {
+		v.add(id, fmt.Sprintf("activity %s: HeartbeatTimeout must be shorter than StartToCloseTimeout", a.Name))
+	}
+}
+
+// parallel 每个分支只能看到并行块之前定义的 binding，结束后合并所有分支的 Result
+func (v *validator) parallel(s *Statement, defined map[string]bool) {
+	if len(s.Parallel.Branches) == 0 {
+		v.add(s.id, "parallel has no branches")
+	}
+	var produced []string
+	for i, b := range s.Parallel.Branches {
+		if b == nil {
+			v.add(fmt.Sprintf("%s/par[%d]", s.id, i), "statement is null")
+			continue
+		}
+		branch := make(map[string]bool, len(defined))
+		for k := range defined {
+			branch[k] = true
+		}
+		v.statement(b, branch)
+		for k := range branch {
+			if !defined[k] {
+				produced = append(produced, k)
+			}
+		}
+	}
+	for _, k := range produced {
+		defined[k] = true
+	}
+}
@@ -0,0 +1,50 @@
+package dsl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"zebra-workflow/internal/secrets"
+)
+
+// Variables DSL 的初始 bindings。JSON 中的值可以是字符串，也可以是 secret 引用 {"secret": "smtp/password"}：
+// 引用按原样（规范化的 JSON 文本）保存在 bindings 和 workflow history 中，只在 worker 执行活动前解析。
+// 其他 JSON 值（数字、对象等）以 JSON 文本保存，与活动结果的保存方式一致。
+type Variables map[string]string
+
+func (v *Variables) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*v = nil
+		return nil
+	}
+	out := make(Variables, len(raw))
+	for k, r := range raw {
+		var s string
+		if err := json.Unmarshal(r, &s); err == nil {
+			out[k] = s
+			continue
+		}
+		var ref struct {
+			Secret *string `json:"secret"`
+		}
+		if err := json.Unmarshal(r, &ref); err == nil && ref.Secret != nil {
+			if *ref.Secret == "" {
+				return fmt.Errorf("variable %q: secret name is empty", k)
+			}
+			out[k] = secrets.Ref(*ref.Secret)
+			continue
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, r); err != nil {
+			return fmt.Errorf("variable %q: %w", k, err)
+		}
+		out[k] = compact.String()
+	}
+	*v = out
+	return nil
+}
@@ -2,18 +2,32 @@ package dsl
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
+
+	"zebra-workflow/internal/schema"
+	"zebra-workflow/internal/tracing"
 )
 
 type (
 	// Workflow is the type used to express the workflow definition. Variables are a map of valuables. Variables can be
-	// used as input to Activity.
+	// used as input to Activity, and may hold secret references (see Variables). Output optionally names the binding
+	// whose value is returned as the workflow result. VariablesSchema and OutputSchema are optional JSON Schemas for
+	// the Variables object and the workflow result (see CheckSchemas).
 	Workflow struct {
-		Variables map[string]string
-		Root      Statement
+		Variables       Variables
+		Root            Statement
+		Output          string
+		VariablesSchema json.RawMessage `json:",omitempty"`
+		OutputSchema    json.RawMessage `json:",omitempty"`
+
+		// rawVariables Variables 的原始 JSON 值，Variables 中只保存文本，按 VariablesSchema 检查时需要原始类型
+		rawVariables map[string]json.RawMessage
 	}
 
 	// Statement is the building block of dsl workflow. A Statement can be a simple ActivityInvocation or it
@@ -22,6 +36,9 @@ type (
 		Activity *ActivityInvocation
 		Sequence *Sequence
 		Parallel *Parallel
+
+		// id 是语句在 DSL 树中的路径（如 root/seq[0]），由 assignIDs 在执行前填充
+		id string
 	}
 
 	// Sequence consist of a collection of Statements that runs in sequential.
@@ -36,11 +53,24 @@ type (
 
 	// ActivityInvocation is used to express invoking an Activity. The Arguments defined expected arguments as input to
 	// the Activity, the result specify the name of variable that it will store the result as which can then be used as
-	// arguments to subsequent ActivityInvocation.
+	// arguments to subsequent ActivityInvocation. An argument "param=binding" passes the binding under a different
+	// parameter name, so two invocations of the same activity can take e.g. "url" from different bindings.
+	// Local runs the activity as a local activity in the worker executing the workflow task, without a round trip
+	// through the task queue; only use it for short, deterministic activities such as RenderTemplate / TransformJSON.
+	// StartToCloseTimeout and HeartbeatTimeout are optional Go durations (e.g. "30m", "1m") overriding the defaults
+	// for this invocation. With a HeartbeatTimeout the activity fails over to another attempt when it stops
+	// heartbeating, so only set it for activities that heartbeat (HttpRequest, ExtractHTML, AsyncActivity); it
+	// cannot be combined with Local.
 	ActivityInvocation struct {
-		Name      string
-		Arguments []string
-		Result    string
+		Name                string
+		Arguments           []string
+		Result              string
+		Local               bool
+		StartToCloseTimeout string `json:",omitempty"`
+		HeartbeatTimeout    string `json:",omitempty"`
+
+		// id 与所在 Statement 的路径相同，用作 ActivityID，便于 trace / history 反查语句
+		id string
 	}
 
 	executable interface {
@@ -48,14 +78,37 @@ type (
 	}
 )
 
+const (
+	// RootStatementID 是根语句的路径，子语句路径形如 root/seq[0]/par[1]
+	RootStatementID = "root"
+
+	// BindingsQuery 查询 DSL 运行中的当前 bindings
+	BindingsQuery = "bindings"
+
+	// statementIDChange 标记“以语句路径作为 ActivityID”的版本变更，保证旧的执行历史可以重放
+	statementIDChange = "dsl-statement-activity-id"
+	// rawStringResultChange 标记“字符串结果原样存入 binding（不再带 JSON 引号）”的版本变更
+	rawStringResultChange = "dsl-raw-string-result"
+)
+
+// rawStringResultKey workflow context 中 rawStringResultChange 的结果（bool）
+type rawStringResultKey struct{}
+
 // SimpleDSLWorkflow workflow definition
-func SimpleDSLWorkflow(ctx workflow.Context, dslWorkflow Workflow) ([]byte, error) {
+func SimpleDSLWorkflow(ctx workflow.Context, dslWorkflow Workflow) (interface{}, error) {
 	bindings := make(map[string]string)
 	//workflowcheck:ignore Only iterates for building another map
 	for k, v := range dslWorkflow.Variables {
 		bindings[k] = v
 	}
 
+	if v := workflow.GetVersion(ctx, statementIDChange, workflow.DefaultVersion, 1); v >= 1 {
+		dslWorkflow.Root.assignIDs(RootStatementID)
+	}
+	// 版本在开始时确定一次：同一次执行中的 binding 格式保持一致，变更前开始的执行（history 中没有 marker）全程使用 JSON 格式
+	rawStrings := workflow.GetVersion(ctx, rawStringResultChange, workflow.DefaultVersion, 1) >= 1
+	ctx = workflow.WithValue(ctx, rawStringResultKey{}, rawStrings)
+
 	ao := workflow.ActivityOptions{
 		StartToCloseTimeout: time.Minute,
 		RetryPolicy: &temporal.RetryPolicy{
@@ -68,31 +121,82 @@ func SimpleDSLWorkflow(ctx workflow.Context, dslWorkflow Workflow) ([]byte, erro
 	ctx = workflow.WithActivityOptions(ctx, ao)
 	logger := workflow.GetLogger(ctx)
 
+	if err := workflow.SetQueryHandler(ctx, BindingsQuery, func() (map[string]string, error) {
+		return bindings, nil
+	}); err != nil {
+		return nil, err
+	}
+
 	err := dslWorkflow.Root.execute(ctx, bindings)
 	if err != nil {
 		logger.Error("DSL Workflow failed.", "Error", err)
 		return nil, err
 	}
 
+	output := outputOf(dslWorkflow.Output, bindings)
+	if len(dslWorkflow.OutputSchema) > 0 {
+		if err := schema.Validate(dslWorkflow.OutputSchema, "output", output); err != nil {
+			logger.Error("DSL Workflow output does not match OutputSchema.", "Error", err)
+			return nil, schema.OutputError(err)
+		}
+	}
 	logger.Info("DSL Workflow completed.")
-	return nil, err
+	return output, nil
+}
+
+// outputOf 取出 Output 指定的 binding 作为 workflow 结果；活动结果以 JSON 保存，能解析时按 JSON 返回
+func outputOf(name string, bindings map[string]string) interface{} {
+	if name == "" {
+		return nil
+	}
+	v, ok := bindings[name]
+	if !ok {
+		return nil
+	}
+	var out interface{}
+	if err := json.Unmarshal([]byte(v), &out); err == nil {
+		return out
+	}
+	return v
+}
+
+// assignIDs 按 DSL 树结构为每个语句分配路径 ID，活动语句以该 ID 作为 ActivityID
+func (b *Statement) assignIDs(id string) {
+	b.id = id
+	if b.Activity != nil {
+		b.Activity.id = id
+	}
+	if b.Sequence != nil {
+		for i, s := range b.Sequence.Elements {
+			if s != nil {
+				s.assignIDs(fmt.Sprintf("%s/seq[%d]", id, i))
+			}
+		}
+	}
+	if b.Parallel != nil {
+		for i, s := range b.Parallel.Branches {
+			if s != nil {
+				s.assignIDs(fmt.Sprintf("%s/par[%d]", id, i))
+			}
+		}
+	}
 }
 
 func (b *Statement) execute(ctx workflow.Context, bindings map[string]string) error {
 	if b.Parallel != nil {
-		err := b.Parallel.execute(ctx, bindings)
+		err := b.observe(ctx, "parallel", func(ctx workflow.Context) error { return b.Parallel.execute(ctx, bindings) })
 		if err != nil {
 			return err
 		}
 	}
 	if b.Sequence != nil {
-		err := b.Sequence.execute(ctx, bindings)
+		err := b.observe(ctx, "sequence", func(ctx workflow.Context) error { return b.Sequence.execute(ctx, bindings) })
 		if err != nil {
 			return err
 		}
 	}
 	if b.Activity != nil {
-		err := b.Activity.execute(ctx, bindings)
+		err := b.observe(ctx, "activity", func(ctx workflow.Context) error { return b.Activity.execute(ctx, bindings) })
 		if err != nil {
 			return err
 		}
@@ -100,15 +204,59 @@ func (b *Statement) execute(ctx workflow.Context, bindings map[string]string) er
 	return nil
 }
 
+// observe 为语句的一部分创建 trace span 并记录指标
+func (b *Statement) observe(ctx workflow.Context, statementType string, fn func(ctx workflow.Context) error) error {
+	attrs := []attribute.KeyValue{
+		attribute.String("dsl.statement", b.id),
+		attribute.String("dsl.statement_type", statementType),
+	}
+	if statementType == "activity" {
+		attrs = append(attrs, attribute.String("dsl.activity", b.Activity.Name))
+	}
+	ctx, end := tracing.StartWorkflowSpan(ctx, "dsl."+statementType, attrs...)
+	err := measureStatement(ctx, statementType, func() error { return fn(ctx) })
+	end(err)
+	return err
+}
+
 func (a ActivityInvocation) execute(ctx workflow.Context, bindings map[string]string) error {
 	inputParam := makePayloadMap(a.Arguments, bindings)
-	var result map[string]string
-	err := workflow.ExecuteActivity(ctx, a.Name, inputParam).Get(ctx, &result)
+	ao := workflow.GetActivityOptions(ctx)
+	if a.id != "" {
+		ao.ActivityID = a.id
+	}
+	timeouts, err := a.timeouts()
+	if err != nil {
+		return temporal.NewNonRetryableApplicationError(err.Error(), "InvalidArgument", nil)
+	}
+	if timeouts.startToClose > 0 {
+		ao.StartToCloseTimeout = timeouts.startToClose
+	}
+	ao.HeartbeatTimeout = timeouts.heartbeat
+	ctx = workflow.WithActivityOptions(ctx, ao)
+	// 活动结果不限定值类型（如 HTTP 状态码、嵌套对象），统一以 JSON 形式保存
+	var result interface{}
+	err = measureActivity(ctx, a.Name, func() error {
+		if a.Local {
+			lctx := workflow.WithLocalActivityOptions(ctx, workflow.LocalActivityOptions{
+				StartToCloseTimeout: ao.StartToCloseTimeout,
+				RetryPolicy:         ao.RetryPolicy,
+			})
+			return workflow.ExecuteLocalActivity(lctx, a.Name, inputParam).Get(lctx, &result)
+		}
+		return workflow.ExecuteActivity(ctx, a.Name, inputParam).Get(ctx, &result)
+	})
 	if err != nil {
 		return err
 	}
-	// 修改 Result 的处理方式，因为它是 map[string]string 类型
 	if len(a.Result) > 0 {
+		// 字符串结果（如 RenderTemplate 渲染出的 URL）原样保存，便于直接作为后续活动的参数
+		if s, ok := result.(string); ok {
+			if raw, _ := ctx.Value(rawStringResultKey{}).(bool); raw {
+				bindings[a.Result] = s
+				return nil
+			}
+		}
 		// 将 result map 转换为 JSON 字符串存储到 bindings 中
 		resultBytes, err := json.Marshal(result)
 		if err != nil {
@@ -119,16 +267,56 @@ func (a ActivityInvocation) execute(ctx workflow.Context, bindings map[string]st
 	return nil
 }
 
+type activityTimeouts struct {
+	startToClose time.Duration
+	heartbeat    time.Duration
+}
+
+// timeouts 解析调用自带的超时，未设置的为 0（使用默认值）
+func (a ActivityInvocation) timeouts() (activityTimeouts, error) {
+	var t activityTimeouts
+	for _, d := range []struct {
+		name  string
+		value string
+		out   *time.Duration
+	}{
+		{"StartToCloseTimeout", a.StartToCloseTimeout, &t.startToClose},
+		{"HeartbeatTimeout", a.HeartbeatTimeout, &t.heartbeat},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil || v <= 0 {
+			return t, fmt.Errorf("activity %s: invalid %s %q", a.Name, d.name, d.value)
+		}
+		*d.out = v
+	}
+	if t.heartbeat > 0 && a.Local {
+		return t, fmt.Errorf("activity %s: local activities do not support HeartbeatTimeout", a.Name)
+	}
+	return t, nil
+}
+
 // makePayloadMap 按 Arguments 名称从 bindings 取出值并构建 map[string]string
 func makePayloadMap(argNames []string, argsMap map[string]string) map[string]string {
 	payload := make(map[string]string)
 	for _, arg := range argNames {
+		param, binding := splitArgument(arg)
 		// 若绑定中不存在该 key，写空字符串（或可根据需要设置默认值）
-		payload[arg] = argsMap[arg]
+		payload[param] = argsMap[binding]
 	}
 	return payload
 }
 
+// splitArgument 拆分 "param=binding" 形式的参数；没有 "=" 时参数名与 binding 名相同
+func splitArgument(arg string) (param, binding string) {
+	if i := strings.Index(arg, "="); i >= 0 {
+		return arg[:i], arg[i+1:]
+	}
+	return arg, arg
+}
+
 func (s Sequence) execute(ctx workflow.Context, bindings map[string]string) error {
 	for _, a := range s.Elements {
 		err := a.execute(ctx, bindings)
@@ -0,0 +1,127 @@
+package dsl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+// versionedDSL 三个活动串联：Greet 返回字符串结果 greeting，两个 Echo 依次原样返回上一步的结果
+func versionedDSL() Workflow {
+	return Workflow{
+		Variables: Variables{"name": "zebra"},
+		Root: Statement{Sequence: &Sequence{Elements: []*Statement{
+			{Activity: &ActivityInvocation{Name: "Greet", Arguments: []string{"name"}, Result: "greeting"}},
+			{Activity: &ActivityInvocation{Name: "Echo", Arguments: []string{"text=greeting"}, Result: "echo"}},
+			{Activity: &ActivityInvocation{Name: "Echo", Arguments: []string{"text=echo"}, Result: "echo2"}},
+		}}},
+		Output: "echo2",
+	}
+}
+
+type startedActivity struct {
+	id   string
+	args map[string]string
+}
+
+// runVersioned 执行 versionedDSL；oldVersions 中的变更按 DefaultVersion 执行，模拟在该变更之前开始的 workflow 的重放
+func runVersioned(t *testing.T, oldVersions ...string) []startedActivity {
+	t.Helper()
+	var s testsuite.WorkflowTestSuite
+	return runVersionedIn(t, s.NewTestWorkflowEnvironment(), nil, oldVersions...)
+}
+
+// runVersionedIn 在 env 中执行 versionedDSL，onStarted 不为 nil 时在每个活动开始时调用
+func runVersionedIn(t *testing.T, env *testsuite.TestWorkflowEnvironment, onStarted func(), oldVersions ...string) []startedActivity {
+	t.Helper()
+	env.RegisterWorkflow(SimpleDSLWorkflow)
+	env.RegisterActivityWithOptions(func(ctx context.Context, in map[string]string) (string, error) {
+		return "hello " + in["name"], nil
+	}, activity.RegisterOptions{Name: "Greet"})
+	env.RegisterActivityWithOptions(func(ctx context.Context, in map[string]string) (string, error) {
+		return in["text"], nil
+	}, activity.RegisterOptions{Name: "Echo"})
+	for _, change := range oldVersions {
+		env.OnGetVersion(change, workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+	}
+	var started []startedActivity
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, args converter.EncodedValues) {
+		var in map[string]string
+		if err := args.Get(&in); err != nil {
+			t.Error(err)
+		}
+		started = append(started, startedActivity{id: info.ActivityID, args: in})
+		if onStarted != nil {
+			onStarted()
+		}
+	})
+
+	env.ExecuteWorkflow(SimpleDSLWorkflow, versionedDSL())
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("workflow did not complete")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Fatal(err)
+	}
+	if len(started) != 3 {
+		t.Fatalf("started %d activities, want 3", len(started))
+	}
+	return started
+}
+
+func TestStatementIDChange(t *testing.T) {
+	started := runVersioned(t)
+	for i, a := range started {
+		if want := fmt.Sprintf("root/seq[%d]", i); a.id != want {
+			t.Errorf("activity %d ID = %q, want %q", i, a.id, want)
+		}
+	}
+
+	// 变更之前的 history 中 ActivityID 由 SDK 按序号生成，重放时必须保持不变
+	started = runVersioned(t, statementIDChange)
+	for _, a := range started {
+		if strings.HasPrefix(a.id, RootStatementID) {
+			t.Errorf("activity ID %q uses the statement path before %s", a.id, statementIDChange)
+		}
+	}
+}
+
+func TestRawStringResultChange(t *testing.T) {
+	started := runVersioned(t)
+	for _, a := range started[1:] {
+		if got := a.args["text"]; got != "hello zebra" {
+			t.Errorf("string result passed as %q, want it unquoted", got)
+		}
+	}
+
+	// history 中没有 marker（变更之前开始的执行）：之后的活动仍然全部按 JSON 格式保存字符串结果，
+	// 不会在执行中途切换格式
+	started = runVersioned(t, rawStringResultChange)
+	if got := started[1].args["text"]; got != `"hello zebra"` {
+		t.Errorf("string result passed as %q before %s, want JSON-quoted", got, rawStringResultChange)
+	}
+	if got, want := started[2].args["text"], `"\"hello zebra\""`; got != want {
+		t.Errorf("later string result passed as %q before %s, want %q", got, rawStringResultChange, want)
+	}
+}
+
+func TestRawStringResultVersionIsDecidedAtStart(t *testing.T) {
+	var s testsuite.WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	decided := false
+	env.OnGetVersion(rawStringResultChange, workflow.DefaultVersion, 1).Run(func(mock.Arguments) {
+		decided = true
+	}).Return(workflow.Version(1))
+	runVersionedIn(t, env, func() {
+		if !decided {
+			t.Error("activity started before the raw string result version was decided")
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"zebra-workflow/internal/auth"
+	"zebra-workflow/internal/schema"
+)
+
+// errorHandler 把 logic 层的授权失败映射为 403（响应中带缺少的权限），schema 校验失败映射为 400（响应中带字段级错误）；
+// 其他错误与 go-zero 默认行为一致：gRPC 错误按状态码映射，其余为 400 纯文本
+func errorHandler(err error) (int, interface{}) {
+	var forbidden *auth.ForbiddenError
+	if errors.As(err, &forbidden) {
+		return http.StatusForbidden, map[string]string{
+			"error":      forbidden.Error(),
+			"permission": forbidden.Permission,
+		}
+	}
+	var invalid *schema.ValidationError
+	if errors.As(err, &invalid) {
+		return http.StatusBadRequest, map[string]interface{}{
+			"error":  invalid.Error(),
+			"fields": invalid.Errors,
+		}
+	}
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return httpStatusOf(status.Code(err)), err
+	}
+	return http.StatusBadRequest, err
+}
+
+func httpStatusOf(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.Canceled:
+		return http.StatusRequestTimeout
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
@@ -3,52 +3,227 @@ package handler
 import (
 	"net/http"
 
+	"zebra-workflow/internal/auth"
+	"zebra-workflow/internal/codec"
+	"zebra-workflow/internal/monitor"
 	"zebra-workflow/internal/temporal"
+	"zebra-workflow/internal/tracing"
 
 	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
-// RegisterRoutes 注册 workflow 相关的路由
-func RegisterRoutes(srv *rest.Server, tc *temporal.ClientWrapper) {
+// RegisterRoutes 注册 workflow 相关的路由。
+// authn 为 nil 时不做认证；/v1/info 和 swagger 始终公开。
+// 访问 Temporal 的路由同时注册 /tenants/:tenant 前缀的版本，也可以用 X-Tenant 头选择租户。
+func RegisterRoutes(srv *rest.Server, tc *temporal.ClientWrapper, authn auth.Authenticator) {
+	authenticate := auth.Middleware(authn)
+	protect := func(next http.HandlerFunc) http.HandlerFunc {
+		return authenticate(withTenant(tc, next))
+	}
+	httpx.SetErrorHandler(errorHandler)
+
 	// Start workflow
-	srv.AddRoute(rest.Route{
+	addTenantRoute(srv, rest.Route{
 		Method:  http.MethodPost,
 		Path:    "/v1/workflow/start",
-		Handler: StartWorkflowHandler(tc),
+		Handler: protect(StartWorkflowHandler(tc)),
 	})
 
 	// Query status
-	srv.AddRoute(rest.Route{
+	addTenantRoute(srv, rest.Route{
 		Method:  http.MethodGet,
 		Path:    "/v1/workflow/:workflowId/status",
-		Handler: QueryStatusHandler(tc),
+		Handler: protect(QueryStatusHandler(tc)),
 	})
 
 	// Signal
-	srv.AddRoute(rest.Route{
+	addTenantRoute(srv, rest.Route{
 		Method:  http.MethodPost,
 		Path:    "/v1/workflow/:workflowId/signal",
-		Handler: SignalHandler(tc),
+		Handler: protect(SignalHandler(tc)),
+	})
+
+	// Result
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodGet,
+		Path:    "/v1/workflow/:workflowId/result",
+		Handler: protect(ResultHandler(tc)),
+	})
+
+	// Timeline（每个活动对应的 DSL 语句、耗时、重试次数）
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodGet,
+		Path:    "/v1/workflow/:workflowId/timeline",
+		Handler: protect(TimelineHandler(tc)),
+	})
+
+	// Cancel
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodPost,
+		Path:    "/v1/workflow/:workflowId/cancel",
+		Handler: protect(CancelHandler(tc)),
+	})
+
+	// Terminate
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodPost,
+		Path:    "/v1/workflow/:workflowId/terminate",
+		Handler: protect(TerminateHandler(tc)),
+	})
+
+	// Reset（重新执行某个 workflow task 之后的部分）
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodPost,
+		Path:    "/v1/workflow/:workflowId/reset",
+		Handler: protect(ResetHandler(tc)),
+	})
+
+	// List（支持 visibility query）
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodGet,
+		Path:    "/v1/workflows",
+		Handler: protect(ListHandler(tc)),
+	})
+
+	// Schedules
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodPost,
+		Path:    "/v1/schedules",
+		Handler: protect(CreateScheduleHandler(tc)),
+	})
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodGet,
+		Path:    "/v1/schedules",
+		Handler: protect(ListSchedulesHandler(tc)),
+	})
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodDelete,
+		Path:    "/v1/schedules/:scheduleId",
+		Handler: protect(ScheduleActionHandler(tc, "delete")),
 	})
+	for _, action := range []string{"pause", "unpause", "trigger"} {
+		addTenantRoute(srv, rest.Route{
+			Method:  http.MethodPost,
+			Path:    "/v1/schedules/:scheduleId/" + action,
+			Handler: protect(ScheduleActionHandler(tc, action)),
+		})
+	}
+
+	// DSL 静态检查
+	addRoute(srv, rest.Route{
+		Method:  http.MethodPost,
+		Path:    "/v1/dsl/validate",
+		Handler: protect(ValidateHandler()),
+	})
+
+	// 活动目录
+	addRoute(srv, rest.Route{
+		Method:  http.MethodGet,
+		Path:    "/v1/activities",
+		Handler: protect(ListActivitiesHandler()),
+	})
+
+	// 外部系统完成 AsyncActivity / 为其心跳
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodPost,
+		Path:    "/v1/activities/complete",
+		Handler: protect(CompleteActivityHandler(tc)),
+	})
+	addTenantRoute(srv, rest.Route{
+		Method:  http.MethodPost,
+		Path:    "/v1/activities/heartbeat",
+		Handler: protect(HeartbeatActivityHandler(tc)),
+	})
+
+	// DSL 离线模拟（不需要 Temporal server）
+	addRoute(srv, rest.Route{
+		Method:  http.MethodPost,
+		Path:    "/v1/dsl/simulate",
+		Handler: protect(SimulateHandler()),
+	})
+
+	// DSL 定义的图（mermaid / dot / json）
+	addRoute(srv, rest.Route{
+		Method:  http.MethodGet,
+		Path:    "/v1/definitions/:name/graph",
+		Handler: protect(DefinitionGraphHandler()),
+	})
+
+	// Codec server（payload 加密或 blob 转存启用时）：Temporal UI 的 codec endpoint 配置为 <server>/codec。
+	// CORS 在认证之前处理，OPTIONS 预检不带凭据
+	if len(tc.PayloadCodecs()) > 0 {
+		origins := tc.CodecAllowedOrigins()
+		for _, path := range []string{"/codec/encode", "/codec/decode"} {
+			addRoute(srv, rest.Route{
+				Method:  http.MethodPost,
+				Path:    path,
+				Handler: codec.CORS(origins, authenticate(CodecHandler(tc))),
+			})
+			addRoute(srv, rest.Route{
+				Method:  http.MethodOptions,
+				Path:    path,
+				Handler: codec.CORS(origins, nil),
+			})
+		}
+	}
 
 	// Info (optional)
-	srv.AddRoute(rest.Route{
+	addRoute(srv, rest.Route{
 		Method:  http.MethodGet,
 		Path:    "/v1/info",
 		Handler: InfoHandler(),
 	})
 
 	// Swagger UI 页面（使用 CDN 的 Swagger UI，指定我们的 spec 地址）
-	srv.AddRoute(rest.Route{
+	addRoute(srv, rest.Route{
 		Method:  http.MethodGet,
 		Path:    "/swagger",
 		Handler: SwaggerUIHandler(),
 	})
 
 	// Swagger OpenAPI 规范（静态文件），会读取仓库中的 docs/openapi.yaml
-	srv.AddRoute(rest.Route{
+	addRoute(srv, rest.Route{
 		Method:  http.MethodGet,
 		Path:    "/swagger/openapi.yaml",
 		Handler: SwaggerSpecHandler(),
 	})
 }
+
+// addRoute 注册路由，并为每个路由记录 Prometheus 请求指标和 trace span（以路由模板作为 route 标签 / span 名称）
+func addRoute(srv *rest.Server, r rest.Route) {
+	r.Handler = monitor.InstrumentHandler(r.Path, tracing.Middleware(r.Path, r.Handler))
+	srv.AddRoute(r)
+}
+
+// addTenantRoute 注册路由及其 /tenants/:tenant 前缀版本
+func addTenantRoute(srv *rest.Server, r rest.Route) {
+	addRoute(srv, r)
+	r.Path = "/tenants/:tenant" + r.Path
+	addRoute(srv, r)
+}
+
+// withTenant 从 /tenants/:tenant 前缀或 X-Tenant 头读取租户放入 context（前缀优先），未配置的租户返回 404，
+// 调用方的角色没有作用于该租户的 grant 时返回 403
+func withTenant(tc *temporal.ClientWrapper, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get("X-Tenant")
+		if t := pathParam(r, "tenant"); t != "" {
+			tenant = t
+		}
+		if tenant == "" {
+			next(w, r)
+			return
+		}
+		if !tc.HasTenant(tenant) {
+			http.Error(w, "unknown tenant "+tenant, http.StatusNotFound)
+			return
+		}
+		ctx, err := auth.SelectTenant(r.Context(), tenant)
+		if err != nil {
+			httpx.Error(w, err)
+			return
+		}
+		next(w, r.WithContext(temporal.WithTenant(ctx, tenant)))
+	}
+}
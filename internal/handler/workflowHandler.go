@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strings"
 
 	"github.com/zeromicro/go-zero/rest/httpx"
+	"github.com/zeromicro/go-zero/rest/pathvar"
+	"go.temporal.io/sdk/converter"
 
 	"zebra-workflow/internal/log"
 	"zebra-workflow/internal/logic"
@@ -79,6 +82,318 @@ func SignalHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
 	}
 }
 
+// TimelineHandler HTTP 层：解析 path + format -> 返回执行时间线（json 或 mermaid Gantt 文本）
+func TimelineHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wid := extractWorkflowID(r)
+		if wid == "" {
+			http.Error(w, "workflowId not found in path", http.StatusBadRequest)
+			return
+		}
+		format := r.URL.Query().Get("format")
+		resp, err := logic.TimelineLogic(r.Context(), tc, wid, format)
+		if err != nil {
+			log.Sugar.Errorw("get workflow timeline failed", "workflowId", wid, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		if text, ok := resp.(string); ok {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(text))
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// ResultHandler HTTP 层：解析 path -> 查询 workflow 结果
+func ResultHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wid := extractWorkflowID(r)
+		if wid == "" {
+			http.Error(w, "workflowId not found in path", http.StatusBadRequest)
+			return
+		}
+		resp, err := logic.ResultLogic(r.Context(), tc, wid)
+		if err != nil {
+			log.Sugar.Errorw("get workflow result failed", "workflowId", wid, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// CancelHandler HTTP 层：解析 path -> 请求取消 workflow
+func CancelHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wid := extractWorkflowID(r)
+		if wid == "" {
+			http.Error(w, "workflowId not found in path", http.StatusBadRequest)
+			return
+		}
+		log.Sugar.Infow("cancel workflow", "workflowId", wid)
+		if err := logic.CancelLogic(r.Context(), tc, wid); err != nil {
+			log.Sugar.Errorw("cancel workflow failed", "workflowId", wid, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.Ok(w)
+	}
+}
+
+// TerminateHandler HTTP 层：解析 path + reason -> 强制终止 workflow
+func TerminateHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wid := extractWorkflowID(r)
+		if wid == "" {
+			http.Error(w, "workflowId not found in path", http.StatusBadRequest)
+			return
+		}
+		var req types.TerminateReq
+		if r.ContentLength > 0 {
+			if err := httpx.Parse(r, &req); err != nil {
+				httpx.Error(w, err)
+				return
+			}
+		}
+		log.Sugar.Infow("terminate workflow", "workflowId", wid, "reason", req.Reason)
+		if err := logic.TerminateLogic(r.Context(), tc, wid, &req); err != nil {
+			log.Sugar.Errorw("terminate workflow failed", "workflowId", wid, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.Ok(w)
+	}
+}
+
+// ResetHandler HTTP 层：解析 path + body -> 重置 workflow，返回新 run 的 ID
+func ResetHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		wid := extractWorkflowID(r)
+		if wid == "" {
+			http.Error(w, "workflowId not found in path", http.StatusBadRequest)
+			return
+		}
+		var req types.ResetReq
+		if r.ContentLength > 0 {
+			if err := httpx.Parse(r, &req); err != nil {
+				httpx.Error(w, err)
+				return
+			}
+		}
+		log.Sugar.Infow("reset workflow", "workflowId", wid, "type", req.Type, "eventId", req.EventID, "reason", req.Reason)
+		resp, err := logic.ResetLogic(r.Context(), tc, wid, &req)
+		if err != nil {
+			log.Sugar.Errorw("reset workflow failed", "workflowId", wid, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// ListHandler HTTP 层：解析 query 参数 -> 列出 workflow
+func ListHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ListReq
+		if err := httpx.Parse(r, &req); err != nil {
+			log.Sugar.Warnw("parse list request failed", "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		resp, err := logic.ListLogic(r.Context(), tc, &req)
+		if err != nil {
+			log.Sugar.Errorw("list workflows failed", "query", req.Query, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// ValidateHandler HTTP 层：解析 DSL -> 静态检查 -> 返回问题列表
+func ValidateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ValidateReq
+		if err := httpx.Parse(r, &req); err != nil {
+			log.Sugar.Warnw("parse validate request failed", "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		resp, err := logic.ValidateLogic(r.Context(), &req)
+		if err != nil {
+			httpx.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// ListActivitiesHandler HTTP 层：返回活动目录（名称、说明、版本、输入 / 输出 schema、错误类型），?name= 只返回指定活动
+func ListActivitiesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httpx.OkJson(w, map[string]interface{}{"activities": logic.ListActivitiesLogic(r.URL.Query().Get("name"))})
+	}
+}
+
+// CompleteActivityHandler HTTP 层：解析 body -> 完成 / 失败 / 取消等待外部完成的活动
+func CompleteActivityHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CompleteActivityReq
+		if err := parseJSONBody(r, &req); err != nil {
+			log.Sugar.Warnw("parse complete activity request failed", "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		log.Sugar.Infow("complete activity", "workflowId", req.WorkflowID, "activityId", req.ActivityID,
+			"byToken", req.TaskToken != "", "failed", req.Error != nil, "canceled", req.Canceled)
+		if err := logic.CompleteActivityLogic(r.Context(), tc, &req); err != nil {
+			log.Sugar.Errorw("complete activity failed", "workflowId", req.WorkflowID, "activityId", req.ActivityID, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.Ok(w)
+	}
+}
+
+// HeartbeatActivityHandler HTTP 层：解析 body -> 为等待外部完成的活动心跳，返回是否已请求取消
+func HeartbeatActivityHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.HeartbeatActivityReq
+		if err := parseJSONBody(r, &req); err != nil {
+			log.Sugar.Warnw("parse heartbeat activity request failed", "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		resp, err := logic.HeartbeatActivityLogic(r.Context(), tc, &req)
+		if err != nil {
+			log.Sugar.Errorw("heartbeat activity failed", "workflowId", req.WorkflowID, "activityId", req.ActivityID, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// parseJSONBody 用标准库解析 JSON 请求体：result / details 可以是任意 JSON 值，httpx.Parse 不支持 interface{} 字段。
+// 数字保留为 json.Number，避免大整数丢失精度
+func parseJSONBody(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
+
+// CreateScheduleHandler HTTP 层：解析 body -> 创建 schedule
+func CreateScheduleHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ScheduleReq
+		if err := httpx.Parse(r, &req); err != nil {
+			log.Sugar.Warnw("parse schedule request failed", "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		log.Sugar.Infow("create schedule", "scheduleId", req.ID, "name", req.Name)
+		if err := logic.CreateScheduleLogic(r.Context(), tc, &req); err != nil {
+			log.Sugar.Errorw("create schedule failed", "scheduleId", req.ID, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.Ok(w)
+	}
+}
+
+// ListSchedulesHandler HTTP 层：列出 schedule
+func ListSchedulesHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := logic.ListSchedulesLogic(r.Context(), tc)
+		if err != nil {
+			httpx.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, map[string]interface{}{"schedules": resp})
+	}
+}
+
+// ScheduleActionHandler HTTP 层：解析 path -> 对 schedule 执行 action（delete / pause / unpause / trigger）
+func ScheduleActionHandler(tc *temporal.ClientWrapper, action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sid := pathParam(r, "scheduleId")
+		if sid == "" {
+			http.Error(w, "scheduleId not found in path", http.StatusBadRequest)
+			return
+		}
+		var req types.ScheduleActionReq
+		if r.ContentLength > 0 {
+			if err := httpx.Parse(r, &req); err != nil {
+				httpx.Error(w, err)
+				return
+			}
+		}
+		log.Sugar.Infow("schedule action", "scheduleId", sid, "action", action)
+		if err := logic.ScheduleActionLogic(r.Context(), tc, sid, action, req.Note); err != nil {
+			httpx.Error(w, err)
+			return
+		}
+		httpx.Ok(w)
+	}
+}
+
+// SimulateHandler HTTP 层：解析 DSL + mocks -> 离线模拟 -> 返回 trace / bindings / output
+func SimulateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.SimulateReq
+		if err := httpx.Parse(r, &req); err != nil {
+			log.Sugar.Warnw("parse simulate request failed", "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		log.Sugar.Infow("simulate dsl request", "mocks", len(req.Mocks))
+		resp, err := logic.SimulateLogic(r.Context(), &req)
+		if err != nil {
+			log.Sugar.Errorw("simulate dsl failed", "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// DefinitionGraphHandler HTTP 层：解析 path + format -> 渲染 DSL 定义的图
+// mermaid / dot 以纯文本返回，json 返回节点和边
+func DefinitionGraphHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := pathParam(r, "name")
+		if name == "" {
+			http.Error(w, "definition name not found in path", http.StatusBadRequest)
+			return
+		}
+		format := r.URL.Query().Get("format")
+		resp, err := logic.DefinitionGraphLogic(r.Context(), name, format)
+		if err != nil {
+			log.Sugar.Errorw("render definition graph failed", "name", name, "format", format, "error", err)
+			httpx.Error(w, err)
+			return
+		}
+		text, ok := resp.(string)
+		if !ok {
+			httpx.OkJson(w, resp)
+			return
+		}
+		contentType := "text/plain; charset=utf-8"
+		if format == "dot" {
+			contentType = "text/vnd.graphviz; charset=utf-8"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(text))
+	}
+}
+
 // InfoHandler 仍然保留（读取 configs/config.yaml 并返回，具体实现可以复用已有代码）
 func InfoHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -143,13 +458,27 @@ func SwaggerSpecHandler() http.HandlerFunc {
 	}
 }
 
-// extractWorkflowID 从 URL path 中按约定提取 /v1/workflow/<id>/... 中的 id
+// extractWorkflowID 返回路由 /v1/workflow/:workflowId/... 中的 workflowId
 func extractWorkflowID(r *http.Request) string {
-	parts := strings.Split(r.URL.Path, "/")
-	for i, p := range parts {
-		if p == "workflow" && i+1 < len(parts) {
-			return parts[i+1]
+	return pathParam(r, "workflowId")
+}
+
+// pathParam 返回路由模板中 :name 对应的值。按路由匹配的结果读取，不扫描 URL，
+// 因此租户名或 ID 与路径中的固定段（如 workflow）相同时也不会取错
+func pathParam(r *http.Request, name string) string {
+	return pathvar.Vars(r)[name]
+}
+
+// CodecHandler Temporal codec server 的 encode / decode（POST .../encode、.../decode，body 为 {"payloads": [...]}），
+// 使用与 client 相同的 codec 链（解密、读取转存的 blob），需要 codec 权限
+func CodecHandler(tc *temporal.ClientWrapper) http.HandlerFunc {
+	next := converter.NewPayloadCodecHTTPHandler(tc.PayloadCodecs()...)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := logic.CodecLogic(r.Context()); err != nil {
+			log.Sugar.Warnw("codec request denied", "path", r.URL.Path, "error", err)
+			httpx.Error(w, err)
+			return
 		}
+		next.ServeHTTP(w, r)
 	}
-	return ""
 }
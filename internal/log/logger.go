@@ -139,7 +139,8 @@ func buildZapConfig(level string, encoding string, outputs []string) (zap.Config
 	cfg := zap.Config{
 		Level:       zap.NewAtomicLevelAt(zapLevel),
 		Development: false,
-		Encoding:    enc,
+		// redact-* 包装标准 encoder，输出前替换 RegisterSecret 登记的 secret
+		Encoding: "redact-" + enc,
 		EncoderConfig: zapcore.EncoderConfig{
 			TimeKey:        "ts",
 			LevelKey:       "level",
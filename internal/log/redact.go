@@ -0,0 +1,158 @@
+package log
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// Mask 替换已登记 secret 的文本
+const Mask = "******"
+
+// minSecretLen 过短的值（如 "1"、"on"）不登记，避免把普通日志内容大面积替换
+const minSecretLen = 4
+
+// 运行时 secret（DSL 引用解析出的值）的上限：超过 maxRuntimeSecrets 个时淘汰最久未登记的，
+// 登记超过 runtimeSecretTTL 未再次登记的也会移除。每次活动执行前都会重新登记，正在使用的值不会过期
+const (
+	maxRuntimeSecrets = 4096
+	runtimeSecretTTL  = 24 * time.Hour
+)
+
+var (
+	secretsMu sync.RWMutex
+	// secretForms 配置中的 secret（worker 启动时登记，数量固定）的原文和 JSON 转义后的形式（json 编码的日志中出现的是后者）
+	secretForms = make(map[string][][]byte)
+	// runtimeSecrets 按最近登记排序（队首最新），元素为 *runtimeSecret；runtimeIndex 原文 -> 元素
+	runtimeSecrets = list.New()
+	runtimeIndex   = make(map[string]*list.Element)
+	now            = time.Now
+)
+
+type runtimeSecret struct {
+	value      string
+	forms      [][]byte
+	registered time.Time
+}
+
+func init() {
+	for _, enc := range []string{"json", "console"} {
+		enc := enc
+		err := zap.RegisterEncoder("redact-"+enc, func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+			if enc == "console" {
+				return &redactingEncoder{zapcore.NewConsoleEncoder(cfg)}, nil
+			}
+			return &redactingEncoder{zapcore.NewJSONEncoder(cfg)}, nil
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterSecret 登记配置中的 secret（如 SMTP 密码、数据库 DSN），之后所有日志输出中的该值都会被替换为 Mask，不会过期
+func RegisterSecret(value string) {
+	if len(value) < minSecretLen {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if _, ok := secretForms[value]; ok {
+		return
+	}
+	secretForms[value] = secretFormsOf(value)
+}
+
+// RegisterRuntimeSecret 登记活动执行前解析出的 secret。与 RegisterSecret 相同地替换日志，
+// 但数量有上限并会过期（见 maxRuntimeSecrets / runtimeSecretTTL），轮换后的旧值不会一直留在内存中
+func RegisterRuntimeSecret(value string) {
+	if len(value) < minSecretLen {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	t := now()
+	if e, ok := runtimeIndex[value]; ok {
+		e.Value.(*runtimeSecret).registered = t
+		runtimeSecrets.MoveToFront(e)
+	} else {
+		runtimeIndex[value] = runtimeSecrets.PushFront(&runtimeSecret{value: value, forms: secretFormsOf(value), registered: t})
+	}
+	for e := runtimeSecrets.Back(); e != nil; e = runtimeSecrets.Back() {
+		rs := e.Value.(*runtimeSecret)
+		if runtimeSecrets.Len() <= maxRuntimeSecrets && t.Sub(rs.registered) < runtimeSecretTTL {
+			break
+		}
+		runtimeSecrets.Remove(e)
+		delete(runtimeIndex, rs.value)
+	}
+}
+
+// secretFormsOf 返回 secret 的原文和 JSON 转义后的形式（与原文不同时）
+func secretFormsOf(value string) [][]byte {
+	forms := [][]byte{[]byte(value)}
+	if quoted, err := json.Marshal(value); err == nil {
+		if escaped := quoted[1 : len(quoted)-1]; !bytes.Equal(escaped, forms[0]) {
+			forms = append(forms, escaped)
+		}
+	}
+	return forms
+}
+
+// Redact 把 s 中已登记的 secret 替换为 Mask
+func Redact(s string) string {
+	return string(redactBytes([]byte(s)))
+}
+
+func redactBytes(b []byte) []byte {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, forms := range secretForms {
+		b = replaceForms(b, forms)
+	}
+	for e := runtimeSecrets.Front(); e != nil; e = e.Next() {
+		b = replaceForms(b, e.Value.(*runtimeSecret).forms)
+	}
+	return b
+}
+
+func replaceForms(b []byte, forms [][]byte) []byte {
+	for _, f := range forms {
+		if bytes.Contains(b, f) {
+			b = bytes.ReplaceAll(b, f, []byte(Mask))
+		}
+	}
+	return b
+}
+
+// redactingEncoder 在编码完成后替换整行日志中的 secret，覆盖 message、字段和 With 附带的上下文
+type redactingEncoder struct {
+	zapcore.Encoder
+}
+
+func (e *redactingEncoder) Clone() zapcore.Encoder {
+	return &redactingEncoder{e.Encoder.Clone()}
+}
+
+func (e *redactingEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return buf, err
+	}
+	secretsMu.RLock()
+	empty := len(secretForms) == 0 && runtimeSecrets.Len() == 0
+	secretsMu.RUnlock()
+	if empty {
+		return buf, nil
+	}
+	redacted := redactBytes(append([]byte(nil), buf.Bytes()...))
+	buf.Reset()
+	_, _ = buf.Write(redacted)
+	return buf, nil
+}
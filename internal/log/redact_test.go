@@ -0,0 +1,49 @@
+package log
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRuntimeSecretsAreBounded(t *testing.T) {
+	t0 := time.Now()
+	now = func() time.Time { return t0 }
+	defer func() { now = time.Now }()
+
+	RegisterRuntimeSecret("first-secret")
+	for i := 0; i < maxRuntimeSecrets; i++ {
+		RegisterRuntimeSecret(fmt.Sprintf("rotated-%05d", i))
+	}
+	if runtimeSecrets.Len() != maxRuntimeSecrets {
+		t.Fatalf("len = %d, want %d", runtimeSecrets.Len(), maxRuntimeSecrets)
+	}
+	if got := Redact("first-secret"); got != "first-secret" {
+		t.Errorf("least recently registered secret was not evicted: %q", got)
+	}
+	if got := Redact(`{"p":"rotated-00001"}`); got != `{"p":"`+Mask+`"}` {
+		t.Errorf("Redact = %q", got)
+	}
+
+	// 重新登记的值刷新过期时间，其余的在 TTL 后移除
+	now = func() time.Time { return t0.Add(runtimeSecretTTL / 2) }
+	RegisterRuntimeSecret("rotated-00001")
+	now = func() time.Time { return t0.Add(runtimeSecretTTL) }
+	RegisterRuntimeSecret("latest-secret")
+	if runtimeSecrets.Len() != 2 {
+		t.Fatalf("len after TTL = %d, want 2", runtimeSecrets.Len())
+	}
+	if got := Redact("rotated-00001 rotated-00002 latest-secret"); got != Mask+" rotated-00002 "+Mask {
+		t.Errorf("Redact = %q", got)
+	}
+}
+
+func TestConfigSecretsDoNotExpire(t *testing.T) {
+	RegisterSecret("smtp-password")
+	now = func() time.Time { return time.Now().Add(2 * runtimeSecretTTL) }
+	defer func() { now = time.Now }()
+	RegisterRuntimeSecret("other-secret")
+	if got := Redact("smtp-password"); got != Mask {
+		t.Errorf("Redact = %q", got)
+	}
+}
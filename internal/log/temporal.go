@@ -0,0 +1,107 @@
+package log
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/activity"
+	tlog "go.temporal.io/sdk/log"
+	"go.uber.org/zap"
+)
+
+// temporalKeys 把 Temporal SDK 自带的字段名统一为本项目日志使用的 camelCase 字段名，
+// 便于按 workflowId / runId 在集中式日志中检索 server、workflow 和 activity 的日志。
+var temporalKeys = map[string]string{
+	"Namespace":    "namespace",
+	"TaskQueue":    "taskQueue",
+	"WorkflowType": "workflowType",
+	"WorkflowID":   "workflowId",
+	"RunID":        "runId",
+	"ActivityType": "activity",
+	"ActivityID":   "activityId",
+	"Attempt":      "attempt",
+	"Error":        "err",
+}
+
+// TemporalLogger 适配 Temporal SDK 的 log.Logger，底层写入全局 zap logger（热重载后自动使用新 logger）。
+// 通过 client.Options.Logger 安装后，workflow.GetLogger 重放时不输出，并自动附带 workflowId、runId 等字段；
+// activity.GetLogger 附带 activity、attempt 等字段。
+type TemporalLogger struct {
+	keyvals []interface{}
+	skip    int
+}
+
+var (
+	_ tlog.Logger          = (*TemporalLogger)(nil)
+	_ tlog.WithLogger      = (*TemporalLogger)(nil)
+	_ tlog.WithSkipCallers = (*TemporalLogger)(nil)
+)
+
+// NewTemporalLogger 返回供 client.Options.Logger 使用的 logger
+func NewTemporalLogger() *TemporalLogger {
+	// 跳过适配器自身的 Debug/Info/Warn/Error
+	return &TemporalLogger{skip: 1}
+}
+
+func (l *TemporalLogger) Debug(msg string, keyvals ...interface{}) {
+	l.sugar().Debugw(msg, renameKeys(keyvals)...)
+}
+
+func (l *TemporalLogger) Info(msg string, keyvals ...interface{}) {
+	l.sugar().Infow(msg, renameKeys(keyvals)...)
+}
+
+func (l *TemporalLogger) Warn(msg string, keyvals ...interface{}) {
+	l.sugar().Warnw(msg, renameKeys(keyvals)...)
+}
+
+func (l *TemporalLogger) Error(msg string, keyvals ...interface{}) {
+	l.sugar().Errorw(msg, renameKeys(keyvals)...)
+}
+
+// With 返回附带固定字段的 logger
+func (l *TemporalLogger) With(keyvals ...interface{}) tlog.Logger {
+	kv := make([]interface{}, 0, len(l.keyvals)+len(keyvals))
+	kv = append(kv, l.keyvals...)
+	kv = append(kv, renameKeys(keyvals)...)
+	return &TemporalLogger{keyvals: kv, skip: l.skip}
+}
+
+// WithCallerSkip SDK 包装 logger（如重放过滤）时调用，使 caller 指向业务代码
+func (l *TemporalLogger) WithCallerSkip(depth int) tlog.Logger {
+	return &TemporalLogger{keyvals: l.keyvals, skip: l.skip + depth}
+}
+
+func (l *TemporalLogger) sugar() *zap.SugaredLogger {
+	return Logger.WithOptions(zap.AddCallerSkip(l.skip)).Sugar().With(l.keyvals...)
+}
+
+// renameKeys 只替换偶数位置（key）上的字符串
+func renameKeys(keyvals []interface{}) []interface{} {
+	out := make([]interface{}, len(keyvals))
+	copy(out, keyvals)
+	for i := 0; i < len(out); i += 2 {
+		if k, ok := out[i].(string); ok {
+			if renamed, ok := temporalKeys[k]; ok {
+				out[i] = renamed
+			}
+		}
+	}
+	return out
+}
+
+// ForActivity 返回附带当前 activity 上下文（workflowId、runId、activity、activityId、attempt）的 logger，
+// 用法与全局 Sugar 一致（Infow / Errorw ...）。ctx 不是 activity context 时（如单元测试）返回全局 Sugar。
+func ForActivity(ctx context.Context) *zap.SugaredLogger {
+	if !activity.IsActivity(ctx) {
+		return Sugar
+	}
+	info := activity.GetInfo(ctx)
+	return Sugar.With(
+		"workflowId", info.WorkflowExecution.ID,
+		"runId", info.WorkflowExecution.RunID,
+		"workflowType", info.WorkflowType.Name,
+		"activity", info.ActivityType.Name,
+		"activityId", info.ActivityID,
+		"attempt", info.Attempt,
+	)
+}
@@ -0,0 +1,100 @@
+package logic
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+
+	sdktemporal "go.temporal.io/sdk/temporal"
+
+	// 内置活动在 init 中登记到活动目录
+	_ "zebra-workflow/internal/activity"
+	"zebra-workflow/internal/auth"
+	"zebra-workflow/internal/catalog"
+	"zebra-workflow/internal/temporal"
+	"zebra-workflow/internal/types"
+)
+
+// ListActivitiesLogic 返回活动目录，name 不为空时只返回该活动（不存在时为空列表）。
+// 插件活动只在 worker 中注册，不在服务端的目录中
+func ListActivitiesLogic(name string) []catalog.Activity {
+	if name == "" {
+		return catalog.List()
+	}
+	a, ok := catalog.Lookup(name)
+	if !ok {
+		return []catalog.Activity{}
+	}
+	return []catalog.Activity{a}
+}
+
+// CompleteActivityLogic 外部系统完成等待中的活动（AsyncActivity）：返回结果、报告失败或确认取消
+func CompleteActivityLogic(ctx context.Context, tc *temporal.ClientWrapper, req *types.CompleteActivityReq) error {
+	if req.Canceled && req.Error != nil {
+		return errors.New("error and canceled cannot be combined")
+	}
+	if req.Result != nil && (req.Canceled || req.Error != nil) {
+		return errors.New("result cannot be combined with error or canceled")
+	}
+	if req.Error != nil && req.Error.Message == "" {
+		return errors.New("error.message is required")
+	}
+	ref, err := activityRef(ctx, tc, &req.ActivityRefReq)
+	if err != nil {
+		return err
+	}
+	var failure error
+	switch {
+	case req.Canceled:
+		var details []interface{}
+		if req.Details != nil {
+			details = append(details, req.Details)
+		}
+		failure = sdktemporal.NewCanceledError(details...)
+	case req.Error != nil:
+		typ := req.Error.Type
+		if typ == "" {
+			typ = "AsyncActivityFailed"
+		}
+		opts := sdktemporal.ApplicationErrorOptions{NonRetryable: req.Error.NonRetryable}
+		if req.Error.Details != nil {
+			opts.Details = []interface{}{req.Error.Details}
+		}
+		failure = sdktemporal.NewApplicationErrorWithOptions(req.Error.Message, typ, opts)
+	}
+	return tc.CompleteActivity(ctx, ref, req.Result, failure)
+}
+
+// HeartbeatActivityLogic 外部系统为等待中的活动心跳，返回活动是否已被请求取消
+func HeartbeatActivityLogic(ctx context.Context, tc *temporal.ClientWrapper, req *types.HeartbeatActivityReq) (*types.HeartbeatActivityResp, error) {
+	ref, err := activityRef(ctx, tc, &req.ActivityRefReq)
+	if err != nil {
+		return nil, err
+	}
+	canceled, err := tc.HeartbeatActivity(ctx, ref, req.Details)
+	if err != nil {
+		return nil, err
+	}
+	return &types.HeartbeatActivityResp{CancelRequested: canceled}, nil
+}
+
+// activityRef 解析活动定位并按 signal 权限授权：workflowId 方式按 workflow 类型检查，
+// taskToken 不透明、无法得知所属 workflow，按 Workflow("*") 检查
+func activityRef(ctx context.Context, tc *temporal.ClientWrapper, req *types.ActivityRefReq) (temporal.ActivityRef, error) {
+	ref := temporal.ActivityRef{WorkflowID: req.WorkflowID, RunID: req.RunID, ActivityID: req.ActivityID}
+	if req.TaskToken != "" {
+		token, err := base64.RawURLEncoding.DecodeString(req.TaskToken)
+		if err != nil {
+			// 兼容标准 base64（带填充）
+			if token, err = base64.StdEncoding.DecodeString(req.TaskToken); err != nil {
+				return ref, errors.New("taskToken is not valid base64")
+			}
+		}
+		ref.TaskToken = token
+		return ref, auth.Authorize(ctx, auth.OpSignal, auth.Workflow("*"))
+	}
+	if req.WorkflowID == "" || req.ActivityID == "" {
+		return ref, errors.New("taskToken or workflowId and activityId are required")
+	}
+	return ref, authorizeExecution(ctx, tc, auth.OpSignal, req.WorkflowID)
+}
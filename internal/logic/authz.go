@@ -0,0 +1,118 @@
+package logic
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"zebra-workflow/internal/auth"
+	"zebra-workflow/internal/temporal"
+	"zebra-workflow/internal/types"
+	"zebra-workflow/internal/workflow"
+)
+
+// authorizeExecution 按 workflow 类型检查调用方对 workflowID 的操作权限，
+// 只有需要授权检查时才查询 workflow 类型
+func authorizeExecution(ctx context.Context, tc *temporal.ClientWrapper, op auth.Operation, workflowID string) error {
+	if !auth.Enforced(ctx) {
+		return nil
+	}
+	name, err := tc.WorkflowType(ctx, workflowID)
+	if err != nil {
+		return err
+	}
+	return auth.Authorize(ctx, op, auth.Workflow(name))
+}
+
+// authorizeDefinition 检查 DSL 定义的 define 权限；没有定义名的 DSL（请求中内联）按 Definition("*") 检查
+func authorizeDefinition(ctx context.Context, name string) error {
+	if name == "" {
+		name = "*"
+	}
+	return auth.Authorize(ctx, auth.OpDefine, auth.Definition(name))
+}
+
+// authorizeStart 检查启动（op 为 start 或 schedule）workflow name 的权限；
+// DSL workflow 执行的是请求中内联的 DSL，还需要对任意定义有 define 权限
+func authorizeStart(ctx context.Context, op auth.Operation, name string) error {
+	if err := auth.Authorize(ctx, op, auth.Workflow(name)); err != nil {
+		return err
+	}
+	if name == workflow.DSLWorkflowName {
+		return authorizeDefinition(ctx, "")
+	}
+	return nil
+}
+
+// authorizeSchedule 按 schedule 启动的 workflow 类型检查 schedule 权限
+func authorizeSchedule(ctx context.Context, tc *temporal.ClientWrapper, scheduleID string) error {
+	if !auth.Enforced(ctx) {
+		return nil
+	}
+	name, err := tc.ScheduleWorkflowType(ctx, scheduleID)
+	if err != nil {
+		return err
+	}
+	return auth.Authorize(ctx, auth.OpSchedule, auth.Workflow(name))
+}
+
+// queryableTypes 返回调用方有 query 权限的已注册 workflow 类型；all 为 true 表示不限制（未启用授权或对任意 workflow 有权限）
+func queryableTypes(ctx context.Context) (names []string, all bool) {
+	if !auth.Enforced(ctx) || auth.Authorize(ctx, auth.OpQuery, auth.Workflow("*")) == nil {
+		return nil, true
+	}
+	seen := map[string]bool{}
+	for _, r := range workflow.ListRegistered() {
+		if seen[r.Name] || strings.ContainsAny(r.Name, `'"\`) {
+			continue
+		}
+		seen[r.Name] = true
+		if auth.Authorize(ctx, auth.OpQuery, auth.Workflow(r.Name)) == nil {
+			names = append(names, r.Name)
+		}
+	}
+	return names, false
+}
+
+// orderBy 匹配 visibility 查询末尾的 ORDER BY 子句
+var orderBy = regexp.MustCompile(`(?is)(^|\s)order\s+by\s.*$`)
+
+// restrictTypes 在 visibility 查询中加上 WorkflowType IN (...) 条件，使分页在服务端按权限过滤，每页都是完整的；
+// 末尾的 ORDER BY 保留在条件之后
+func restrictTypes(query string, names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = "'" + n + "'"
+	}
+	cond := "WorkflowType IN (" + strings.Join(quoted, ", ") + ")"
+	order := orderBy.FindString(query)
+	where := strings.TrimSpace(strings.TrimSuffix(query, order))
+	if where != "" {
+		cond = "(" + where + ") AND " + cond
+	}
+	if order = strings.TrimSpace(order); order != "" {
+		cond += " " + order
+	}
+	return cond
+}
+
+// filterExecutions 列表中只保留调用方有 query 权限的 workflow（restrictTypes 之外的保险）
+func filterExecutions(ctx context.Context, in []types.ExecutionSummary) []types.ExecutionSummary {
+	if !auth.Enforced(ctx) {
+		return in
+	}
+	out := make([]types.ExecutionSummary, 0, len(in))
+	for _, e := range in {
+		if auth.Authorize(ctx, auth.OpQuery, auth.Workflow(e.Type)) == nil {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// canQuerySchedule 调用方是否有 schedule 所启动 workflow 的 query 权限
+func canQuerySchedule(ctx context.Context) func(workflowType string) bool {
+	return func(workflowType string) bool {
+		return auth.Authorize(ctx, auth.OpQuery, auth.Workflow(workflowType)) == nil
+	}
+}
@@ -0,0 +1,19 @@
+package logic
+
+import "testing"
+
+func TestRestrictTypes(t *testing.T) {
+	names := []string{"BillingFlow", "DSLWorkflow"}
+	tests := map[string]string{
+		"":                            "WorkflowType IN ('BillingFlow', 'DSLWorkflow')",
+		"ExecutionStatus='Running'":   "(ExecutionStatus='Running') AND WorkflowType IN ('BillingFlow', 'DSLWorkflow')",
+		"A=1 OR B=2":                  "(A=1 OR B=2) AND WorkflowType IN ('BillingFlow', 'DSLWorkflow')",
+		"A=1 order by StartTime desc": "(A=1) AND WorkflowType IN ('BillingFlow', 'DSLWorkflow') order by StartTime desc",
+		"ORDER BY CloseTime":          "WorkflowType IN ('BillingFlow', 'DSLWorkflow') ORDER BY CloseTime",
+	}
+	for query, want := range tests {
+		if got := restrictTypes(query, names); got != want {
+			t.Errorf("restrictTypes(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
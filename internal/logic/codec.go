@@ -0,0 +1,13 @@
+package logic
+
+import (
+	"context"
+
+	"zebra-workflow/internal/auth"
+)
+
+// CodecLogic 检查 /codec/encode、/codec/decode 的权限；payload 可能来自任何 workflow，按 Workflow("*") 检查。
+// 编解码本身由 SDK 的 codec HTTP handler 完成
+func CodecLogic(ctx context.Context) error {
+	return auth.Authorize(ctx, auth.OpCodec, auth.Workflow("*"))
+}
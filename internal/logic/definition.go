@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zeromicro/go-zero/core/conf"
+
+	"zebra-workflow/internal/definition"
+	dslpkg "zebra-workflow/internal/dsl"
+	"zebra-workflow/internal/workflow"
+)
+
+const defaultDefinitionsDir = "configs/definitions"
+
+// definitionStore 读取 configs/config.yaml 中的 definitions.dir 并创建定义仓库
+func definitionStore() (*definition.Store, error) {
+	var cfg struct {
+		Definitions struct {
+			Dir string `yaml:"dir" json:"dir,optional"`
+		} `yaml:"definitions" json:"definitions,optional"`
+	}
+	if err := conf.Load("configs/config.yaml", &cfg); err != nil {
+		return nil, err
+	}
+	dir := cfg.Definitions.Dir
+	if dir == "" {
+		dir = defaultDefinitionsDir
+	}
+	return definition.NewStore(dir), nil
+}
+
+// loadDefinition 读取并解析名为 name 的 DSL 定义
+func loadDefinition(name string) (dslpkg.Workflow, error) {
+	store, err := definitionStore()
+	if err != nil {
+		return dslpkg.Workflow{}, err
+	}
+	raw, err := store.Load(name)
+	if err != nil {
+		return dslpkg.Workflow{}, err
+	}
+	return workflow.ParseDSLInput(raw)
+}
+
+// DefinitionGraphLogic 把 DSL 定义渲染为 mermaid / dot 文本或 json 图结构
+func DefinitionGraphLogic(ctx context.Context, name string, format string) (interface{}, error) {
+	if err := authorizeDefinition(ctx, name); err != nil {
+		return nil, err
+	}
+	w, err := loadDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+	g := dslpkg.ToGraph(w)
+	switch format {
+	case "", "mermaid":
+		return g.Mermaid(), nil
+	case "dot":
+		return g.DOT(), nil
+	case "json":
+		return g, nil
+	default:
+		return nil, fmt.Errorf("unsupported graph format %q (mermaid, dot or json)", format)
+	}
+}
@@ -0,0 +1,91 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"zebra-workflow/internal/auth"
+	"zebra-workflow/internal/temporal"
+	"zebra-workflow/internal/types"
+)
+
+// TimelineLogic 返回 workflow 的执行时间线，format 为 json（默认）或 mermaid（Gantt 图）
+func TimelineLogic(ctx context.Context, tc *temporal.ClientWrapper, workflowID string, format string) (interface{}, error) {
+	if err := authorizeExecution(ctx, tc, auth.OpQuery, workflowID); err != nil {
+		return nil, err
+	}
+	t, err := tc.GetTimeline(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "", "json":
+		return t, nil
+	case "mermaid":
+		return timelineGantt(t, time.Now()), nil
+	default:
+		return nil, fmt.Errorf("unsupported timeline format %q (json or mermaid)", format)
+	}
+}
+
+// timelineGantt 渲染 Mermaid Gantt：每个语句一个 section，排队时间和执行时间各占一条；
+// 未结束的步骤以 now 作为结束时间并标记为 active，失败 / 超时标记为 crit
+func timelineGantt(t *types.TimelineResp, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("gantt\n")
+	fmt.Fprintf(&sb, "    title %s (%s)\n", ganttText(t.WorkflowID), ganttText(t.Status))
+	sb.WriteString("    dateFormat x\n    axisFormat %H:%M:%S\n")
+	for i, s := range t.Steps {
+		section := s.Statement
+		if section == "" {
+			section = "activity " + s.ActivityID
+		}
+		fmt.Fprintf(&sb, "    section %s\n", ganttText(section))
+
+		scheduled := parseMillis(s.ScheduledTime, now)
+		started := parseMillis(s.StartedTime, now)
+		closed := parseMillis(s.CloseTime, now)
+		if s.StartedTime != "" && started > scheduled {
+			fmt.Fprintf(&sb, "    %s queued :done, q%d, %d, %d\n", ganttText(s.Activity), i, scheduled, started)
+		}
+		if s.StartedTime == "" {
+			started = scheduled
+		}
+		tag := "done"
+		switch s.Status {
+		case "failed", "timedOut":
+			tag = "crit"
+		case "scheduled", "running":
+			tag = "active"
+		}
+		name := s.Activity
+		if s.Attempt > 1 {
+			name = fmt.Sprintf("%s (attempt %d)", s.Activity, s.Attempt)
+		}
+		// Mermaid 不会绘制零长度的任务，至少保留 1ms
+		if closed <= started {
+			closed = started + 1
+		}
+		fmt.Fprintf(&sb, "    %s :%s, a%d, %d, %d\n", ganttText(name), tag, i, started, closed)
+	}
+	return sb.String()
+}
+
+// parseMillis 把 RFC3339 时间转为 Unix 毫秒，空值使用 now
+func parseMillis(s string, now time.Time) int64 {
+	if s == "" {
+		return now.UnixMilli()
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return now.UnixMilli()
+	}
+	return t.UnixMilli()
+}
+
+// ganttText 去掉 Mermaid Gantt 语法中有特殊含义的字符
+func ganttText(s string) string {
+	return strings.NewReplacer(":", " ", ";", " ", "#", " ", "\n", " ").Replace(s)
+}
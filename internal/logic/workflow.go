@@ -2,32 +2,208 @@ package logic
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 
 	"github.com/zeromicro/go-zero/core/conf"
 
+	"zebra-workflow/internal/auth"
+	dslpkg "zebra-workflow/internal/dsl"
 	"zebra-workflow/internal/temporal"
 	"zebra-workflow/internal/types"
+	"zebra-workflow/internal/workflow"
 )
 
 // StartWorkflowLogic 业务层：真正调用 temporal client 启动 workflow
 func StartWorkflowLogic(ctx context.Context, tc *temporal.ClientWrapper, req *types.StartReq) (*types.StartResp, error) {
-	wid, rid, err := tc.StartWorkflow(ctx, req.Name, req.Version, req.Input)
+	if err := authorizeStart(ctx, auth.OpStart, req.Name); err != nil {
+		return nil, err
+	}
+	if err := workflow.ValidateInput(req.Name, req.Input); err != nil {
+		return nil, err
+	}
+	wid, rid, err := tc.StartWorkflow(ctx, req.Name, req.Version, req.Input, startedByMemo(ctx))
 	if err != nil {
 		return nil, err
 	}
 	return &types.StartResp{WorkflowID: wid, RunID: rid}, nil
 }
 
+// startedByMemo 把认证通过的调用方记录到 workflow memo（未启用认证时为空）
+func startedByMemo(ctx context.Context) map[string]interface{} {
+	p := auth.FromContext(ctx)
+	if p == nil {
+		return nil
+	}
+	return map[string]interface{}{"startedBy": p.String()}
+}
+
 // QueryStatusLogic 查询 workflow 状态（封装 temporal 调用）
 func QueryStatusLogic(ctx context.Context, tc *temporal.ClientWrapper, workflowID string) (map[string]interface{}, error) {
+	if err := authorizeExecution(ctx, tc, auth.OpQuery, workflowID); err != nil {
+		return nil, err
+	}
 	return tc.QueryWorkflowStatus(ctx, workflowID)
 }
 
 // SignalLogic 向 workflow 发送 signal
 func SignalLogic(ctx context.Context, tc *temporal.ClientWrapper, workflowID string, req *types.SignalReq) error {
+	if err := authorizeExecution(ctx, tc, auth.OpSignal, workflowID); err != nil {
+		return err
+	}
 	return tc.SendSignal(ctx, workflowID, req.SignalName, req.Payload)
 }
 
+// ResultLogic 查询 workflow 结果
+func ResultLogic(ctx context.Context, tc *temporal.ClientWrapper, workflowID string) (*types.ResultResp, error) {
+	if err := authorizeExecution(ctx, tc, auth.OpQuery, workflowID); err != nil {
+		return nil, err
+	}
+	return tc.GetWorkflowResult(ctx, workflowID)
+}
+
+// CancelLogic 请求取消 workflow
+func CancelLogic(ctx context.Context, tc *temporal.ClientWrapper, workflowID string) error {
+	if err := authorizeExecution(ctx, tc, auth.OpCancel, workflowID); err != nil {
+		return err
+	}
+	return tc.CancelWorkflow(ctx, workflowID)
+}
+
+// TerminateLogic 强制终止 workflow
+func TerminateLogic(ctx context.Context, tc *temporal.ClientWrapper, workflowID string, req *types.TerminateReq) error {
+	if err := authorizeExecution(ctx, tc, auth.OpTerminate, workflowID); err != nil {
+		return err
+	}
+	reason := req.Reason
+	if p := auth.FromContext(ctx); p != nil {
+		reason = fmt.Sprintf("%s (terminated by %s)", reason, p)
+	}
+	return tc.TerminateWorkflow(ctx, workflowID, reason)
+}
+
+// ResetLogic 把 workflow 重置到指定的 workflow task 之后重新执行
+func ResetLogic(ctx context.Context, tc *temporal.ClientWrapper, workflowID string, req *types.ResetReq) (*types.ResetResp, error) {
+	if err := authorizeExecution(ctx, tc, auth.OpReset, workflowID); err != nil {
+		return nil, err
+	}
+	reason := req.Reason
+	if p := auth.FromContext(ctx); p != nil {
+		reason = fmt.Sprintf("%s (reset by %s)", reason, p)
+	}
+	runID, err := tc.ResetWorkflow(ctx, workflowID, req.Type, req.EventID, reason)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ResetResp{WorkflowID: workflowID, RunID: runID}, nil
+}
+
+// ListLogic 分页列出 workflow，pageSize 默认 20
+func ListLogic(ctx context.Context, tc *temporal.ClientWrapper, req *types.ListReq) (*types.ListResp, error) {
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	var token []byte
+	if req.NextPageToken != "" {
+		t, err := base64.StdEncoding.DecodeString(req.NextPageToken)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nextPageToken: %w", err)
+		}
+		token = t
+	}
+	query := req.Query
+	if names, all := queryableTypes(ctx); !all {
+		if len(names) == 0 {
+			return &types.ListResp{Executions: []types.ExecutionSummary{}}, nil
+		}
+		query = restrictTypes(query, names)
+	}
+	resp, err := tc.ListWorkflows(ctx, query, pageSize, token)
+	if err != nil {
+		return nil, err
+	}
+	resp.Executions = filterExecutions(ctx, resp.Executions)
+	return resp, nil
+}
+
+// ValidateLogic 静态检查 DSL，问题列表随 200 返回，只有 input 无法解析时才返回 error
+func ValidateLogic(ctx context.Context, req *types.ValidateReq) (*types.ValidateResp, error) {
+	if err := authorizeDefinition(ctx, req.Name); err != nil {
+		return nil, err
+	}
+	w, err := workflow.ParseDSLInput(req.Input)
+	if err != nil {
+		return nil, err
+	}
+	err = dslpkg.Validate(w)
+	if err == nil {
+		return &types.ValidateResp{Valid: true}, nil
+	}
+	var verr *dslpkg.ValidationError
+	if !errors.As(err, &verr) {
+		return nil, err
+	}
+	resp := &types.ValidateResp{Valid: false}
+	for _, p := range verr.Problems {
+		resp.Problems = append(resp.Problems, types.Problem{Statement: p.Statement, Message: p.Message})
+	}
+	return resp, nil
+}
+
+// CreateScheduleLogic 创建 schedule
+func CreateScheduleLogic(ctx context.Context, tc *temporal.ClientWrapper, req *types.ScheduleReq) error {
+	if req.ID == "" || req.Name == "" {
+		return errors.New("schedule id and name are required")
+	}
+	if len(req.Cron) == 0 && req.Interval == "" {
+		return errors.New("schedule needs cron or interval")
+	}
+	if err := authorizeStart(ctx, auth.OpSchedule, req.Name); err != nil {
+		return err
+	}
+	if err := workflow.ValidateInput(req.Name, req.Input); err != nil {
+		return err
+	}
+	return tc.CreateSchedule(ctx, req, startedByMemo(ctx))
+}
+
+// ListSchedulesLogic 列出 schedule
+func ListSchedulesLogic(ctx context.Context, tc *temporal.ClientWrapper) ([]types.ScheduleSummary, error) {
+	return tc.ListSchedules(ctx, canQuerySchedule(ctx))
+}
+
+// ScheduleActionLogic 对 schedule 执行 delete / pause / unpause / trigger
+func ScheduleActionLogic(ctx context.Context, tc *temporal.ClientWrapper, scheduleID string, action string, note string) error {
+	if err := authorizeSchedule(ctx, tc, scheduleID); err != nil {
+		return err
+	}
+	return tc.ScheduleAction(ctx, scheduleID, action, note)
+}
+
+// SimulateLogic 离线模拟 DSL：不连接 Temporal server，活动返回值由请求中的 mocks 提供
+func SimulateLogic(ctx context.Context, req *types.SimulateReq) (*dslpkg.SimulationResult, error) {
+	if err := authorizeDefinition(ctx, req.Name); err != nil {
+		return nil, err
+	}
+	w, err := workflow.ParseDSLInput(req.Input)
+	if err != nil {
+		return nil, err
+	}
+	mocks := make([]dslpkg.ActivityMock, 0, len(req.Mocks))
+	for _, m := range req.Mocks {
+		mocks = append(mocks, dslpkg.ActivityMock{
+			Activity:     m.Activity,
+			Call:         m.Call,
+			Output:       m.Output,
+			Error:        m.Error,
+			NonRetryable: m.NonRetryable,
+		})
+	}
+	return dslpkg.Simulate(w, req.Variables, mocks)
+}
+
 // InfoLogic 读取 configs/config.yaml 并返回 InfoResp
 func InfoLogic() (*types.InfoResp, error) {
 	var cfg struct {
@@ -0,0 +1,102 @@
+package monitor
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/uber-go/tally/v4"
+	tallyprom "github.com/uber-go/tally/v4/prometheus"
+	"go.temporal.io/sdk/client"
+	sdktally "go.temporal.io/sdk/contrib/tally"
+
+	logger "zebra-workflow/internal/log"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "zebra_http_requests_total",
+		Help: "HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "zebra_http_request_duration_seconds",
+		Help:    "HTTP request latency by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	temporalOnce    sync.Once
+	temporalHandler client.MetricsHandler
+)
+
+func init() {
+	prometheus.MustRegister(httpRequests, httpDuration)
+}
+
+// Serve 在 addr 上暴露 /metrics（默认 registry，包含 HTTP、Temporal SDK 和 DSL 指标），后台运行
+func Serve(addr string) {
+	if addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		logger.Sugar.Infow("prometheus metrics endpoint started", "addr", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Sugar.Errorw("prometheus metrics endpoint stopped", "addr", addr, "err", err)
+		}
+	}()
+}
+
+// InstrumentHandler 记录请求数和耗时，route 使用路由模板（如 /v1/workflow/:workflowId/status）以控制标签基数
+func InstrumentHandler(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+		httpRequests.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+		httpDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// TemporalMetricsHandler 返回 Temporal SDK 使用的 MetricsHandler：SDK 指标（含 workflow.GetMetricsHandler 记录的
+// DSL 指标）经 tally 写入默认 prometheus registry。进程内所有 client 共享同一个 handler，避免重复注册。
+func TemporalMetricsHandler() client.MetricsHandler {
+	temporalOnce.Do(func() {
+		reporter := tallyprom.NewReporter(tallyprom.Options{
+			Registerer:       prometheus.DefaultRegisterer,
+			DefaultTimerType: tallyprom.HistogramTimerType,
+			OnRegisterError: func(err error) {
+				logger.Sugar.Warnw("register temporal metric failed", "err", err)
+			},
+		})
+		scope, _ := tally.NewRootScope(tally.ScopeOptions{
+			CachedReporter:  reporter,
+			Separator:       tallyprom.DefaultSeparator,
+			SanitizeOptions: &sdktally.PrometheusSanitizeOptions,
+		}, time.Second)
+		temporalHandler = sdktally.NewMetricsHandler(sdktally.NewPrometheusNamingScope(scope))
+	})
+	return temporalHandler
+}
+
+// statusRecorder 记录 handler 写出的状态码
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
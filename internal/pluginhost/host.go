@@ -0,0 +1,253 @@
+// Package pluginhost 启动进程外活动插件（见 pkg/plugin），把插件提供的活动登记到活动目录；worker 将其注册为代理活动。
+package pluginhost
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+
+	"zebra-workflow/internal/catalog"
+	logger "zebra-workflow/internal/log"
+	"zebra-workflow/pkg/plugin"
+	"zebra-workflow/pkg/plugin/pluginpb"
+)
+
+const defaultStartTimeout = 10 * time.Second
+
+// Config 映射 plugins
+type Config struct {
+	// Dir 插件目录，目录下每个可执行文件是一个插件；为空时不加载插件
+	Dir string `json:"dir,optional" yaml:"dir"`
+	// StartTimeout 插件启动并返回 Describe 的超时，默认 10s
+	StartTimeout string `json:"startTimeout,optional" yaml:"startTimeout"`
+}
+
+// Host 管理所有已启动的插件
+type Host struct {
+	plugins []*Plugin
+	// activities 活动名 -> 提供该活动的插件
+	activities map[string]*Plugin
+}
+
+// Plugin 一个插件进程；进程退出后下一次执行时重新启动
+type Plugin struct {
+	name    string
+	path    string
+	timeout time.Duration
+
+	mu     sync.Mutex
+	client *goplugin.Client
+	rpc    pluginpb.ActivityPluginClient
+
+	// Activities Describe 返回的活动
+	Activities []*pluginpb.ActivityDescriptor
+}
+
+// Discover 启动 cfg.Dir 下的全部插件并读取活动描述。单个插件启动失败或活动名与其他插件重复时记录错误并跳过，
+// 不影响 worker 启动
+func Discover(cfg Config) (*Host, error) {
+	h := &Host{activities: make(map[string]*Plugin)}
+	if cfg.Dir == "" {
+		return h, nil
+	}
+	timeout := defaultStartTimeout
+	if cfg.StartTimeout != "" {
+		d, err := time.ParseDuration(cfg.StartTimeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("plugins: invalid startTimeout %q", cfg.StartTimeout)
+		}
+		timeout = d
+	}
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("plugins: read dir: %w", err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") || !e.Type().IsRegular() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.Mode()&0o111 == 0 {
+			continue
+		}
+		p := &Plugin{name: e.Name(), path: filepath.Join(cfg.Dir, e.Name()), timeout: timeout}
+		if err := p.describe(); err != nil {
+			logger.Sugar.Errorw("failed to load activity plugin", "plugin", p.name, "err", err)
+			p.kill()
+			continue
+		}
+		var names []string
+		for _, a := range p.Activities {
+			if other, ok := h.activities[a.GetName()]; ok {
+				logger.Sugar.Errorw("plugin activity already provided by another plugin, skipped",
+					"plugin", p.name, "activity", a.GetName(), "provider", other.name)
+				continue
+			}
+			h.activities[a.GetName()] = p
+			names = append(names, a.GetName())
+		}
+		h.plugins = append(h.plugins, p)
+		logger.Sugar.Infow("activity plugin loaded", "plugin", p.name, "activities", names)
+	}
+	return h, nil
+}
+
+// RegisterCatalog 把插件活动登记到活动目录，GET /v1/activities 和 DSL 静态检查据此读取插件声明的 schema；
+// Func 为代理活动，由 catalog.RegisterWorker 注册到 worker。与目录中已有活动（内置活动）重名或 schema 不是合法 JSON 的
+// 活动记录错误后跳过
+func (h *Host) RegisterCatalog() {
+	for _, a := range h.Activities() {
+		p := h.activities[a.GetName()]
+		if _, ok := catalog.Lookup(a.GetName()); ok {
+			logger.Sugar.Errorw("plugin activity conflicts with a registered activity, skipped",
+				"plugin", p.name, "activity", a.GetName())
+			continue
+		}
+		entry := catalog.Activity{
+			Name:        a.GetName(),
+			Description: a.GetDescription(),
+			Version:     "plugin:" + p.name,
+			// 代理活动转发插件的心跳
+			Heartbeat: true,
+			Func:      p.proxy(a.GetName()),
+		}
+		if s := a.GetInputSchema(); s != "" {
+			entry.InputSchema = json.RawMessage(s)
+		}
+		if s := a.GetOutputSchema(); s != "" {
+			entry.OutputSchema = json.RawMessage(s)
+		}
+		if !validSchemas(entry) {
+			logger.Sugar.Errorw("plugin activity schema is not valid JSON, skipped", "plugin", p.name, "activity", a.GetName())
+			continue
+		}
+		catalog.Register(entry)
+	}
+}
+
+func validSchemas(a catalog.Activity) bool {
+	for _, s := range []json.RawMessage{a.InputSchema, a.OutputSchema} {
+		if len(s) > 0 && !json.Valid(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// Activities 返回全部插件活动的描述，按名称排序
+func (h *Host) Activities() []*pluginpb.ActivityDescriptor {
+	var out []*pluginpb.ActivityDescriptor
+	for _, p := range h.plugins {
+		for _, a := range p.Activities {
+			if h.activities[a.GetName()] == p {
+				out = append(out, a)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].GetName() < out[j].GetName() })
+	return out
+}
+
+// Close 结束所有插件进程
+func (h *Host) Close() {
+	for _, p := range h.plugins {
+		p.kill()
+	}
+}
+
+// describe 启动插件并读取活动描述
+func (p *Plugin) describe() error {
+	rpc, err := p.conn()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	resp, err := rpc.Describe(ctx, &pluginpb.DescribeRequest{})
+	if err != nil {
+		return fmt.Errorf("describe: %w", err)
+	}
+	for _, a := range resp.GetActivities() {
+		if a.GetName() == "" {
+			return fmt.Errorf("describe: activity without name")
+		}
+	}
+	p.Activities = resp.GetActivities()
+	return nil
+}
+
+// conn 返回插件的 gRPC client，插件未启动或已退出时（重新）启动
+func (p *Plugin) conn() (pluginpb.ActivityPluginClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client != nil && !p.client.Exited() {
+		return p.rpc, nil
+	}
+	if p.client != nil {
+		logger.Sugar.Warnw("activity plugin exited, restarting", "plugin", p.name)
+	}
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig:  plugin.Handshake,
+		Plugins:          plugin.PluginSet(),
+		Cmd:              exec.Command(p.path),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolGRPC},
+		StartTimeout:     p.timeout,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   "plugin." + p.name,
+			Level:  hclog.Info,
+			Output: logWriter{plugin: p.name},
+		}),
+	})
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("start plugin %s: %w", p.name, err)
+	}
+	raw, err := rpcClient.Dispense(plugin.Name)
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("start plugin %s: %w", p.name, err)
+	}
+	p.client, p.rpc = client, raw.(pluginpb.ActivityPluginClient)
+	return p.rpc, nil
+}
+
+func (p *Plugin) kill() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client != nil {
+		p.client.Kill()
+	}
+}
+
+// logWriter 把 go-plugin 的日志（含插件 stderr）写入 worker 日志
+type logWriter struct {
+	plugin string
+}
+
+func (w logWriter) Write(b []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		if line != "" {
+			logger.Sugar.Infow("plugin output", "plugin", w.plugin, "line", line)
+		}
+	}
+	return len(b), nil
+}
+
+func newExecutionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,125 @@
+package pluginhost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+
+	logger "zebra-workflow/internal/log"
+	"zebra-workflow/pkg/plugin/pluginpb"
+)
+
+// cancelTimeout 通知插件取消执行的超时
+const cancelTimeout = 5 * time.Second
+
+// proxy 返回注册到 worker 的代理活动：把输入和上次心跳转发给插件，插件的心跳转为活动心跳，
+// 活动被取消时通知插件取消
+func (p *Plugin) proxy(name string) func(ctx context.Context, input map[string]string) (interface{}, error) {
+	return func(ctx context.Context, input map[string]string) (interface{}, error) {
+		return p.execute(ctx, name, input)
+	}
+}
+
+func (p *Plugin) execute(ctx context.Context, name string, input map[string]string) (interface{}, error) {
+	rpc, err := p.conn()
+	if err != nil {
+		return nil, err
+	}
+	info := activity.GetInfo(ctx)
+	req := &pluginpb.ExecuteRequest{
+		ExecutionId: newExecutionID(),
+		Activity:    name,
+		Input:       input,
+		Info: &pluginpb.ActivityInfo{
+			Namespace:  info.WorkflowNamespace,
+			TaskQueue:  info.TaskQueue,
+			WorkflowId: info.WorkflowExecution.ID,
+			RunId:      info.WorkflowExecution.RunID,
+			ActivityId: info.ActivityID,
+			Attempt:    info.Attempt,
+		},
+	}
+	if activity.HasHeartbeatDetails(ctx) {
+		var details json.RawMessage
+		if err := activity.GetHeartbeatDetails(ctx, &details); err == nil {
+			req.HeartbeatDetails = details
+		}
+	}
+
+	log := logger.ForActivity(ctx)
+	stream, err := rpc.Execute(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: execute %s: %w", p.name, name, err)
+	}
+	// 活动取消（需要活动定期心跳才能收到）时先通知插件，再由 ctx 结束 gRPC 流
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+			defer cancel()
+			if _, err := rpc.Cancel(cctx, &pluginpb.CancelRequest{ExecutionId: req.ExecutionId}); err != nil {
+				log.Warnw("failed to cancel plugin activity", "plugin", p.name, "err", err)
+			}
+		case <-done:
+		}
+	}()
+
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("plugin %s: %s finished without a result", p.name, name)
+			}
+			// 插件进程退出等：按 RetryPolicy 重试，下一次执行会重启插件
+			return nil, fmt.Errorf("plugin %s: %s: %w", p.name, name, err)
+		}
+		switch e := ev.GetEvent().(type) {
+		case *pluginpb.ExecuteEvent_Heartbeat:
+			if d := e.Heartbeat.GetDetails(); len(d) > 0 {
+				activity.RecordHeartbeat(ctx, json.RawMessage(d))
+			} else {
+				activity.RecordHeartbeat(ctx)
+			}
+		case *pluginpb.ExecuteEvent_Result:
+			var result interface{}
+			if raw := e.Result.GetJson(); len(raw) > 0 {
+				if err := json.Unmarshal(raw, &result); err != nil {
+					return nil, fmt.Errorf("plugin %s: %s: decode result: %w", p.name, name, err)
+				}
+			}
+			return result, nil
+		case *pluginpb.ExecuteEvent_Error:
+			return nil, applicationError(e.Error)
+		}
+	}
+}
+
+// applicationError 把插件返回的错误转换为 ApplicationError
+func applicationError(e *pluginpb.Error) error {
+	opts := temporal.ApplicationErrorOptions{
+		NonRetryable:   e.GetNonRetryable(),
+		NextRetryDelay: time.Duration(e.GetNextRetryDelayMs()) * time.Millisecond,
+	}
+	if d := e.GetDetails(); len(d) > 0 {
+		var details interface{}
+		if err := json.Unmarshal(d, &details); err == nil {
+			opts.Details = []interface{}{details}
+		}
+	}
+	errType := e.GetType()
+	if errType == "" {
+		errType = "PluginError"
+	}
+	return temporal.NewApplicationErrorWithOptions(e.GetMessage(), errType, opts)
+}
@@ -0,0 +1,157 @@
+// Package schema 用 JSON Schema（默认 draft 2020-12）校验 workflow 的输入和输出，校验失败时给出字段级错误。
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"go.temporal.io/sdk/temporal"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// schemaURL 编译时 schema 的虚拟地址，不会被访问
+const schemaURL = "mem://zebra/schema.json"
+
+var printer = message.NewPrinter(language.English)
+
+// FieldError 单个字段的校验错误，Field 为 JSON Pointer（如 /to、/items/0/id，根为 /）
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError 值不符合 schema，Subject 说明被校验的对象（input、variables、output）
+type ValidationError struct {
+	Subject string
+	Errors  []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, f := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.Field, f.Message))
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Subject, strings.Join(msgs, "; "))
+}
+
+// OutputMismatchType workflow 返回值不符合 OutputSchema 时的错误类型
+const OutputMismatchType = "OutputSchemaMismatch"
+
+// OutputError 把 workflow 返回值的校验错误转换为使 workflow 失败的不可重试错误，字段级错误放在 details 中
+func OutputError(err error) error {
+	if verr, ok := err.(*ValidationError); ok {
+		return temporal.NewNonRetryableApplicationError(verr.Error(), OutputMismatchType, nil, verr.Errors)
+	}
+	return temporal.NewNonRetryableApplicationError(err.Error(), "InvalidArgument", nil)
+}
+
+// Schema 编译好的 JSON Schema，可并发使用
+type Schema struct {
+	compiled *jsonschema.Schema
+}
+
+// Compile 编译 schema。不加载外部资源：$ref 只能引用 schema 自身（#/$defs/...）
+func Compile(raw json.RawMessage) (*Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	c := jsonschema.NewCompiler()
+	c.UseLoader(noLoader{})
+	if err := c.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	compiled, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &Schema{compiled: compiled}, nil
+}
+
+// Validate 校验 v（任意可 JSON 序列化的值），返回 nil 或 *ValidationError
+func (s *Schema) Validate(subject string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%s is not JSON: %w", subject, err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("%s is not JSON: %w", subject, err)
+	}
+	err = s.compiled.Validate(doc)
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+	out := &ValidationError{Subject: subject}
+	seen := make(map[FieldError]bool)
+	collect(verr, func(f FieldError) {
+		if !seen[f] {
+			seen[f] = true
+			out.Errors = append(out.Errors, f)
+		}
+	})
+	sort.SliceStable(out.Errors, func(i, j int) bool { return out.Errors[i].Field < out.Errors[j].Field })
+	return out
+}
+
+// Validate 编译 raw 并校验 v；raw 为空时不校验
+func Validate(raw json.RawMessage, subject string, v interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	s, err := Compile(raw)
+	if err != nil {
+		return fmt.Errorf("%s schema: %w", subject, err)
+	}
+	return s.Validate(subject, v)
+}
+
+// collect 只取错误树的叶子（具体的关键字错误），allOf / $ref 等中间节点只说明“子 schema 不匹配”
+func collect(e *jsonschema.ValidationError, add func(FieldError)) {
+	if len(e.Causes) > 0 {
+		for _, c := range e.Causes {
+			collect(c, add)
+		}
+		return
+	}
+	switch k := e.ErrorKind.(type) {
+	case *kind.Reference:
+		return
+	case *kind.Required:
+		// 缺少的属性按属性自身的路径报告
+		for _, name := range k.Missing {
+			add(FieldError{Field: pointer(append(append([]string(nil), e.InstanceLocation...), name)), Message: "required"})
+		}
+		return
+	}
+	add(FieldError{Field: pointer(e.InstanceLocation), Message: e.ErrorKind.LocalizedString(printer)})
+}
+
+func pointer(tokens []string) string {
+	if len(tokens) == 0 {
+		return "/"
+	}
+	var sb strings.Builder
+	for _, t := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(t))
+	}
+	return sb.String()
+}
+
+// noLoader 拒绝加载外部 schema，避免调用方提交的 schema 读取 worker / 服务端的文件或访问网络
+type noLoader struct{}
+
+func (noLoader) Load(url string) (any, error) {
+	return nil, fmt.Errorf("external schema %s is not allowed", url)
+}
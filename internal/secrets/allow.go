@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// allowRule 允许 secret 名称匹配 secret 的引用传给匹配 activity / param 的活动参数；param 为空时不限参数
+type allowRule struct {
+	secret   string
+	activity string
+	param    string
+}
+
+// allowlist 见 Config.Allow，为空时不允许任何 secret
+type allowlist []allowRule
+
+func newAllowlist(cfg map[string][]string) (allowlist, error) {
+	var rules allowlist
+	for secret, targets := range cfg {
+		for _, target := range targets {
+			activity, param, _ := strings.Cut(target, ".")
+			r := allowRule{secret: secret, activity: activity, param: param}
+			for _, pattern := range []string{r.secret, r.activity, r.param} {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return nil, fmt.Errorf("secrets: invalid allow pattern %q for %s: %w", pattern, secret, err)
+				}
+			}
+			if r.activity == "" {
+				return nil, fmt.Errorf("secrets: allow entry %q for %s has no activity", target, secret)
+			}
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+func (l allowlist) allows(secret, activity, param string) bool {
+	for _, r := range l {
+		if match(r.secret, secret) && match(r.activity, activity) && (r.param == "" || match(r.param, param)) {
+			return true
+		}
+	}
+	return false
+}
+
+func match(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+type mapProvider map[string]string
+
+func (m mapProvider) Resolve(_ context.Context, name string) (string, error) {
+	v, ok := m[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func TestAllowlist(t *testing.T) {
+	l, err := newAllowlist(map[string][]string{
+		"example/token": {"HttpRequest.apiToken"},
+		"smtp/*":        {"SendEmail"},
+		"db/password":   {"SQL*.dsn*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		secret, activity, param string
+		want                    bool
+	}{
+		{"example/token", "HttpRequest", "apiToken", true},
+		{"example/token", "HttpRequest", "body", false},
+		{"example/token", "SendEmail", "apiToken", false},
+		{"smtp/password", "SendEmail", "text", true},
+		{"smtp/password", "SendEmail", "", true},
+		{"smtp/password", "HttpRequest", "body", false},
+		{"db/password", "SQLQuery", "dsnPassword", true},
+		{"db/password", "SQLQuery", "query", false},
+		{"other", "HttpRequest", "apiToken", false},
+	}
+	for _, tt := range tests {
+		if got := l.allows(tt.secret, tt.activity, tt.param); got != tt.want {
+			t.Errorf("allows(%s, %s, %s) = %v, want %v", tt.secret, tt.activity, tt.param, got, tt.want)
+		}
+	}
+
+	for name, cfg := range map[string]map[string][]string{
+		"secret pattern":   {"[a-": {"HttpRequest"}},
+		"activity pattern": {"token": {"Http[.body"}},
+		"no activity":      {"token": {".body"}},
+	} {
+		if _, err := newAllowlist(cfg); err == nil {
+			t.Errorf("%s: invalid allow entry accepted", name)
+		}
+	}
+}
+
+func TestInterceptorRejectsSecretsNotAllowed(t *testing.T) {
+	i, err := NewInterceptor(mapProvider{"example/token": "s3cr3t", "smtp/password": "hunter2"},
+		map[string][]string{"example/token": {"HttpRequest.apiToken"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(in map[string]string) (map[string]string, error) {
+		var s testsuite.WorkflowTestSuite
+		env := s.NewTestActivityEnvironment()
+		env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{i}})
+		var seen map[string]string
+		env.RegisterActivityWithOptions(func(ctx context.Context, in map[string]string) (string, error) {
+			seen = in
+			return "ok", nil
+		}, activity.RegisterOptions{Name: "HttpRequest"})
+		_, err := env.ExecuteActivity("HttpRequest", in)
+		return seen, err
+	}
+
+	seen, err := run(map[string]string{"apiToken": Ref("example/token"), "url": "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen["apiToken"] != "s3cr3t" {
+		t.Errorf("apiToken = %q, want the resolved secret", seen["apiToken"])
+	}
+
+	for name, in := range map[string]map[string]string{
+		"other parameter": {"body": Ref("example/token")},
+		"other secret":    {"apiToken": Ref("smtp/password")},
+	} {
+		seen, err := run(in)
+		var appErr *temporal.ApplicationError
+		if !errors.As(err, &appErr) || appErr.Type() != "SecretNotAllowed" || !appErr.NonRetryable() {
+			t.Errorf("%s: err = %v, want non-retryable SecretNotAllowed", name, err)
+		}
+		if seen != nil {
+			t.Errorf("%s: activity ran with %v", name, seen)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvConfig secret 名称转换为环境变量名：非字母数字替换为 "_" 并转为大写，再加上 Prefix
+// （如 Prefix "ZEBRA_SECRET_"：smtp/password -> ZEBRA_SECRET_SMTP_PASSWORD）。
+// Prefix 必填，否则 DSL 可以通过 secret 引用读取 worker 的任意环境变量
+type EnvConfig struct {
+	Prefix string `json:"prefix,optional" yaml:"prefix"`
+}
+
+// EnvProvider 从环境变量读取 secret
+type EnvProvider struct {
+	prefix string
+}
+
+// DefaultEnvPrefix 未配置 secrets 时 env provider 使用的前缀
+const DefaultEnvPrefix = "ZEBRA_SECRET_"
+
+func NewEnvProvider(cfg EnvConfig) (*EnvProvider, error) {
+	if cfg.Prefix == "" {
+		return nil, errors.New("secrets: env.prefix is required")
+	}
+	return &EnvProvider{prefix: cfg.Prefix}, nil
+}
+
+func (p *EnvProvider) Resolve(_ context.Context, name string) (string, error) {
+	key := p.envName(name)
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s (environment variable %s)", ErrNotFound, name, key)
+	}
+	return v, nil
+}
+
+func (p *EnvProvider) envName(name string) string {
+	return p.prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,94 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"zebra-workflow/internal/codec"
+)
+
+// FileConfig 本地加密文件：内容为 {"keyId": "...", "data": "<base64>"}，解密后是 {"smtp/password": "..."}。
+// 使用与 payload 加密相同格式的 keyring（可以是同一个文件），用 zebractl secrets seal 生成
+type FileConfig struct {
+	Path        string `json:"path,optional" yaml:"path"`
+	KeyringFile string `json:"keyringFile,optional" yaml:"keyringFile"`
+}
+
+// sealedFile 加密文件的格式
+type sealedFile struct {
+	KeyID string `json:"keyId"`
+	Data  string `json:"data"`
+}
+
+// sealedAAD 加密文件使用的附加认证数据，避免与 payload 密文互换
+var sealedAAD = []byte("zebra-secrets")
+
+// FileProvider 启动时解密整个文件，secret 只保存在内存中；修改文件后需重启 worker
+type FileProvider struct {
+	values map[string]string
+}
+
+func NewFileProvider(cfg FileConfig) (*FileProvider, error) {
+	if cfg.Path == "" || cfg.KeyringFile == "" {
+		return nil, errors.New("secrets: file.path and file.keyringFile are required")
+	}
+	ring, err := codec.LoadKeyring(cfg.KeyringFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: %w", err)
+	}
+	values, err := Open(ring, data)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: %s: %w", cfg.Path, err)
+	}
+	return &FileProvider{values: values}, nil
+}
+
+func (p *FileProvider) Resolve(_ context.Context, name string) (string, error) {
+	v, ok := p.values[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return v, nil
+}
+
+// Seal 用 keyring 的 current key 加密 secret 表，返回加密文件的内容
+func Seal(ring *codec.Keyring, values map[string]string) ([]byte, error) {
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	keyID, data, err := ring.Seal(plain, sealedAAD)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(sealedFile{KeyID: keyID, Data: base64.StdEncoding.EncodeToString(data)}, "", "  ")
+}
+
+// Open 解密 Seal 生成的内容
+func Open(ring *codec.Keyring, content []byte) (map[string]string, error) {
+	var f sealedFile
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(f.Data)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := ring.Open(f.KeyID, data, sealedAAD)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,169 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// NewInterceptor 返回 worker interceptor：活动执行前把参数中 allow 允许的 secret 引用替换为 provider 解析出的值，
+// 执行后把结果和错误信息中出现的这些值替换为日志使用的 Mask，保证明文不进入 history。allow 见 Config.Allow
+func NewInterceptor(p Provider, allow map[string][]string) (interceptor.WorkerInterceptor, error) {
+	rules, err := newAllowlist(allow)
+	if err != nil {
+		return nil, err
+	}
+	return &workerInterceptor{provider: p, allow: rules}, nil
+}
+
+type workerInterceptor struct {
+	interceptor.WorkerInterceptorBase
+	provider Provider
+	allow    allowlist
+}
+
+func (w *workerInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	i := &activityInbound{provider: w.provider, allow: w.allow}
+	i.Next = next
+	return i
+}
+
+type activityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+	provider Provider
+	allow    allowlist
+}
+
+func (a *activityInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	var resolved []string
+	name := activity.GetInfo(ctx).ActivityType.Name
+	for i, arg := range in.Args {
+		v, values, err := a.resolveArg(ctx, name, arg)
+		if err != nil {
+			return nil, err
+		}
+		in.Args[i] = v
+		resolved = append(resolved, values...)
+	}
+	result, err := a.Next.ExecuteActivity(ctx, in)
+	if len(resolved) == 0 {
+		return result, err
+	}
+	return redactValue(result, resolved), redactError(err, resolved)
+}
+
+// resolveArg 支持 DSL 活动使用的参数形态：map[string]string、map[string]interface{}（字符串值）和 string（参数名为空）
+func (a *activityInbound) resolveArg(ctx context.Context, activityName string, arg interface{}) (interface{}, []string, error) {
+	var resolved []string
+	resolve := func(param, s string) (string, error) {
+		name, ok := ParseRef(s)
+		if !ok {
+			return s, nil
+		}
+		if !a.allow.allows(name, activityName, param) {
+			return "", temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("secret %s is not allowed for activity %s parameter %q", name, activityName, param), "SecretNotAllowed", nil)
+		}
+		v, err := a.provider.Resolve(ctx, name)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return "", temporal.NewNonRetryableApplicationError("unable to resolve secret "+name, "SecretNotFound", err)
+			}
+			return "", temporal.NewApplicationErrorWithCause("unable to resolve secret "+name, "SecretUnavailable", err)
+		}
+		logger.RegisterRuntimeSecret(v)
+		resolved = append(resolved, v)
+		return v, nil
+	}
+
+	switch v := arg.(type) {
+	case string:
+		s, err := resolve("", v)
+		return s, resolved, err
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for k, s := range v {
+			r, err := resolve(k, s)
+			if err != nil {
+				return nil, nil, err
+			}
+			out[k] = r
+		}
+		return out, resolved, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			if s, ok := x.(string); ok {
+				r, err := resolve(k, s)
+				if err != nil {
+					return nil, nil, err
+				}
+				x = r
+			}
+			out[k] = x
+		}
+		return out, resolved, nil
+	default:
+		return arg, nil, nil
+	}
+}
+
+// redactValue 通过 JSON 往返替换结果中的 secret，无法序列化或不含 secret 时原样返回
+func redactValue(v interface{}, values []string) interface{} {
+	if v == nil {
+		return v
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	redacted := string(b)
+	for _, s := range values {
+		if len(s) == 0 {
+			continue
+		}
+		quoted, _ := json.Marshal(s)
+		redacted = strings.ReplaceAll(redacted, string(quoted[1:len(quoted)-1]), logger.Mask)
+	}
+	if redacted == string(b) {
+		return v
+	}
+	out := reflect.New(reflect.TypeOf(v))
+	if err := json.Unmarshal([]byte(redacted), out.Interface()); err != nil {
+		return logger.Mask
+	}
+	return out.Elem().Interface()
+}
+
+// redactError 错误信息包含 secret 时替换为同类型、同重试语义的 ApplicationError
+func redactError(err error, values []string) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	for _, s := range values {
+		if len(s) > 0 {
+			msg = strings.ReplaceAll(msg, s, logger.Mask)
+		}
+	}
+	if msg == err.Error() {
+		return err
+	}
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		return temporal.NewApplicationErrorWithOptions(msg, appErr.Type(), temporal.ApplicationErrorOptions{
+			NonRetryable:   appErr.NonRetryable(),
+			NextRetryDelay: appErr.NextRetryDelay(),
+		})
+	}
+	return temporal.NewApplicationError(msg, "")
+}
@@ -0,0 +1,70 @@
+// Package secrets 解析 DSL 中的 secret 引用 {"secret": "smtp/password"}。
+// 引用原样保存在 workflow history 中，只在 worker 执行活动前由 Provider 解析（环境变量、本地加密文件或 Vault），
+// 解析出的值登记到日志脱敏，并从活动结果中替换掉，不会进入 history 和 query 结果。
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound secret 不存在
+var ErrNotFound = errors.New("secret not found")
+
+// Provider 按名称（如 "smtp/password"）返回 secret 的值
+type Provider interface {
+	Resolve(ctx context.Context, name string) (string, error)
+}
+
+// Config 映射 worker 配置中的 secrets
+type Config struct {
+	// Provider env（默认，未配置 env.prefix 时使用 DefaultEnvPrefix）、file 或 vault；显式选择 env 时 env.prefix 必填
+	Provider string      `json:"provider,optional" yaml:"provider"`
+	Env      EnvConfig   `json:"env,optional" yaml:"env"`
+	File     FileConfig  `json:"file,optional" yaml:"file"`
+	Vault    VaultConfig `json:"vault,optional" yaml:"vault"`
+	// Allow secret 名称（可用 path.Match 模式）-> 允许接收该 secret 的活动参数："活动名"（任意参数）或 "活动名.参数名"，
+	// 同样支持模式。未列出的 secret 或活动参数以 SecretNotAllowed 失败
+	Allow map[string][]string `json:"allow,optional" yaml:"allow"`
+}
+
+// New 按配置创建 Provider
+func New(cfg Config) (Provider, error) {
+	switch cfg.Provider {
+	case "":
+		// 没有 secrets 配置时只暴露默认前缀的环境变量
+		if cfg.Env.Prefix == "" {
+			cfg.Env.Prefix = DefaultEnvPrefix
+		}
+		return NewEnvProvider(cfg.Env)
+	case "env":
+		return NewEnvProvider(cfg.Env)
+	case "file":
+		return NewFileProvider(cfg.File)
+	case "vault":
+		return NewVaultProvider(cfg.Vault)
+	default:
+		return nil, fmt.Errorf("secrets: unknown provider %q", cfg.Provider)
+	}
+}
+
+// Ref 返回 secret 引用在 bindings / 活动参数中的文本形式
+func Ref(name string) string {
+	b, _ := json.Marshal(map[string]string{"secret": name})
+	return string(b)
+}
+
+// ParseRef 判断值是否为 secret 引用（只有 "secret" 一个字段的 JSON 对象），返回 secret 名称
+func ParseRef(value string) (string, bool) {
+	if len(value) < 2 || value[0] != '{' {
+		return "", false
+	}
+	var ref map[string]string
+	if err := json.Unmarshal([]byte(value), &ref); err != nil || len(ref) != 1 {
+		return "", false
+	}
+	name, ok := ref["secret"]
+	return name, ok && name != ""
+}
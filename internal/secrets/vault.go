@@ -0,0 +1,188 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultConfig Vault（或兼容 API，如 OpenBao）的 KV 引擎。secret 名称的最后一段是字段名，其余是路径：
+// smtp/password -> <mount>/data/smtp 中的 password 字段（KV v2）；没有 "/" 时读取 value 字段
+type VaultConfig struct {
+	Address string `json:"address,optional" yaml:"address"`
+	// Mount KV 引擎的挂载路径，默认 secret
+	Mount string `json:"mount,optional" yaml:"mount"`
+	// KVVersion 1 或 2（默认）
+	KVVersion int `json:"kvVersion,optional" yaml:"kvVersion"`
+	// TokenEnv 保存 token 的环境变量名，默认 VAULT_TOKEN
+	TokenEnv string `json:"tokenEnv,optional" yaml:"tokenEnv"`
+	// Namespace Vault Enterprise namespace（X-Vault-Namespace）
+	Namespace string `json:"namespace,optional" yaml:"namespace"`
+	// CacheTTL 读取结果的缓存时间，默认 5m，"0s" 表示不缓存
+	CacheTTL string `json:"cacheTTL,optional" yaml:"cacheTTL"`
+}
+
+// VaultProvider 通过 HTTP API 读取 KV secret，同一路径的字段共享一次请求的结果
+type VaultProvider struct {
+	address   string
+	mount     string
+	kvVersion int
+	token     string
+	namespace string
+	ttl       time.Duration
+	http      *http.Client
+
+	mu    sync.Mutex
+	cache map[string]vaultEntry
+}
+
+type vaultEntry struct {
+	data    map[string]interface{}
+	expires time.Time
+}
+
+func NewVaultProvider(cfg VaultConfig) (*VaultProvider, error) {
+	if cfg.Address == "" {
+		return nil, errors.New("secrets: vault.address is required")
+	}
+	p := &VaultProvider{
+		address:   strings.TrimSuffix(cfg.Address, "/"),
+		mount:     strings.Trim(cfg.Mount, "/"),
+		kvVersion: cfg.KVVersion,
+		namespace: cfg.Namespace,
+		ttl:       5 * time.Minute,
+		http:      &http.Client{Timeout: 10 * time.Second},
+		cache:     make(map[string]vaultEntry),
+	}
+	if p.mount == "" {
+		p.mount = "secret"
+	}
+	if p.kvVersion == 0 {
+		p.kvVersion = 2
+	}
+	if p.kvVersion != 1 && p.kvVersion != 2 {
+		return nil, fmt.Errorf("secrets: vault.kvVersion must be 1 or 2, got %d", p.kvVersion)
+	}
+	tokenEnv := cfg.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = "VAULT_TOKEN"
+	}
+	if p.token = os.Getenv(tokenEnv); p.token == "" {
+		return nil, fmt.Errorf("secrets: environment variable %s is empty", tokenEnv)
+	}
+	if cfg.CacheTTL != "" {
+		d, err := time.ParseDuration(cfg.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("secrets: invalid vault.cacheTTL %q: %w", cfg.CacheTTL, err)
+		}
+		p.ttl = d
+	}
+	return p, nil
+}
+
+func (p *VaultProvider) Resolve(ctx context.Context, name string) (string, error) {
+	path, field, err := vaultPath(name)
+	if err != nil {
+		return "", err
+	}
+	data, err := p.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	v, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// vaultPath 把 secret 名称拆分为转义后的 KV 路径和字段名。名称来自 DSL，拒绝空段、"." 和 ".."，
+// 避免 secret 引用读取 mount 之外的 Vault API
+func vaultPath(name string) (path, field string, err error) {
+	segments := strings.Split(name, "/")
+	for _, s := range segments {
+		if s == "" || s == "." || s == ".." {
+			return "", "", fmt.Errorf("secrets: invalid vault secret name %q", name)
+		}
+	}
+	if len(segments) == 1 {
+		return url.PathEscape(name), "value", nil
+	}
+	field = segments[len(segments)-1]
+	segments = segments[:len(segments)-1]
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/"), field, nil
+}
+
+func (p *VaultProvider) read(ctx context.Context, path string) (map[string]interface{}, error) {
+	p.mu.Lock()
+	if e, ok := p.cache[path]; ok && time.Now().Before(e.expires) {
+		p.mu.Unlock()
+		return e.data, nil
+	}
+	p.mu.Unlock()
+
+	endpoint := fmt.Sprintf("%s/v1/%s/%s", p.address, p.mount, path)
+	if p.kvVersion == 2 {
+		endpoint = fmt.Sprintf("%s/v1/%s/data/%s", p.address, p.mount, path)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: vault request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("secrets: vault response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: vault path %s", ErrNotFound, path)
+	case resp.StatusCode != http.StatusOK:
+		// 不返回响应体，避免泄露 Vault 的错误细节
+		return nil, fmt.Errorf("secrets: vault %s returned %s", path, resp.Status)
+	}
+
+	var parsed struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("secrets: decode vault response: %w", err)
+	}
+	data := parsed.Data
+	if p.kvVersion == 2 {
+		inner, _ := data["data"].(map[string]interface{})
+		data = inner
+	}
+	if data == nil {
+		return nil, fmt.Errorf("%w: vault path %s", ErrNotFound, path)
+	}
+	if p.ttl > 0 {
+		p.mu.Lock()
+		p.cache[path] = vaultEntry{data: data, expires: time.Now().Add(p.ttl)}
+		p.mu.Unlock()
+	}
+	return data, nil
+}
@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVaultPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		field string
+		ok    bool
+	}{
+		{name: "token", path: "token", field: "value", ok: true},
+		{name: "smtp/password", path: "smtp", field: "password", ok: true},
+		{name: "team a/db/password", path: "team%20a/db", field: "password", ok: true},
+		{name: "a?b/c", path: "a%3Fb", field: "c", ok: true},
+		{name: "", ok: false},
+		{name: "/smtp/password", ok: false},
+		{name: "smtp//password", ok: false},
+		{name: "smtp/password/", ok: false},
+		{name: "../sys/password", ok: false},
+		{name: "smtp/../password", ok: false},
+		{name: "./smtp/password", ok: false},
+		{name: "..", ok: false},
+	}
+	for _, tt := range tests {
+		path, field, err := vaultPath(tt.name)
+		if tt.ok != (err == nil) {
+			t.Errorf("vaultPath(%q) error = %v, want ok %v", tt.name, err, tt.ok)
+			continue
+		}
+		if tt.ok && (path != tt.path || field != tt.field) {
+			t.Errorf("vaultPath(%q) = %q, %q, want %q, %q", tt.name, path, field, tt.path, tt.field)
+		}
+	}
+}
+
+func TestVaultResolveEscapesPath(t *testing.T) {
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.EscapedPath()
+		w.Write([]byte(`{"data": {"data": {"password": "s3cret"}}}`))
+	}))
+	defer srv.Close()
+	t.Setenv("VAULT_TOKEN", "test")
+	p, err := NewVaultProvider(VaultConfig{Address: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := p.Resolve(context.Background(), "team a/db/password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "s3cret" || requested != "/v1/secret/data/team%20a/db" {
+		t.Errorf("got %q from %s", v, requested)
+	}
+
+	requested = ""
+	if _, err := p.Resolve(context.Background(), "../../sys/policy/password"); err == nil || requested != "" {
+		t.Errorf("traversal name was not rejected: err %v, requested %q", err, requested)
+	}
+}
+
+func TestEnvPrefixRequired(t *testing.T) {
+	if _, err := New(Config{Provider: "env"}); err == nil {
+		t.Error("env provider without prefix should be rejected")
+	}
+	t.Setenv("ZEBRA_SECRET_SMTP_PASSWORD", "pw")
+	t.Setenv("SMTP_PASSWORD", "leaked")
+	p, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := p.Resolve(context.Background(), "smtp/password"); err != nil || v != "pw" {
+		t.Errorf("default prefix: got %q, %v", v, err)
+	}
+}
@@ -0,0 +1,74 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+
+	sdktemporal "go.temporal.io/sdk/temporal"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// ActivityRef 定位一个等待外部完成的活动：TaskToken，或 WorkflowID + ActivityID（RunID 为空时为最新的 run）
+type ActivityRef struct {
+	TaskToken  []byte
+	WorkflowID string
+	RunID      string
+	ActivityID string
+}
+
+func (r ActivityRef) validate() error {
+	if len(r.TaskToken) == 0 && (r.WorkflowID == "" || r.ActivityID == "") {
+		return errors.New("taskToken or workflowId and activityId are required")
+	}
+	return nil
+}
+
+// CompleteActivity 以 result 完成活动；err 不为 nil 时活动失败（CanceledError 表示外部系统已取消）
+func (c *ClientWrapper) CompleteActivity(ctx context.Context, ref ActivityRef, result interface{}, err error) error {
+	if verr := ref.validate(); verr != nil {
+		return verr
+	}
+	tc, cerr := c.scoped(ctx)
+	if cerr != nil {
+		return cerr
+	}
+	if len(ref.TaskToken) > 0 {
+		cerr = tc.cli.CompleteActivity(ctx, ref.TaskToken, result, err)
+	} else {
+		cerr = tc.cli.CompleteActivityByID(ctx, tc.namespace, ref.WorkflowID, ref.RunID, ref.ActivityID, result, err)
+	}
+	if cerr != nil {
+		logger.Sugar.Errorw("complete activity failed", "workflowId", ref.WorkflowID, "activityId", ref.ActivityID, "err", cerr)
+		return cerr
+	}
+	return nil
+}
+
+// HeartbeatActivity 代外部系统为活动心跳，details 在活动重试时可以取回。返回活动是否已被请求取消
+func (c *ClientWrapper) HeartbeatActivity(ctx context.Context, ref ActivityRef, details interface{}) (bool, error) {
+	if err := ref.validate(); err != nil {
+		return false, err
+	}
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return false, err
+	}
+	var args []interface{}
+	if details != nil {
+		args = append(args, details)
+	}
+	if len(ref.TaskToken) > 0 {
+		err = tc.cli.RecordActivityHeartbeat(ctx, ref.TaskToken, args...)
+	} else {
+		err = tc.cli.RecordActivityHeartbeatByID(ctx, tc.namespace, ref.WorkflowID, ref.RunID, ref.ActivityID, args...)
+	}
+	if sdktemporal.IsCanceledError(err) {
+		return true, nil
+	}
+	if err != nil {
+		logger.Sugar.Errorw("record activity heartbeat failed", "workflowId", ref.WorkflowID, "activityId", ref.ActivityID, "err", err)
+		return false, err
+	}
+	return false, nil
+}
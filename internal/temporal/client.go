@@ -2,11 +2,25 @@ package temporal
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
+
+	"zebra-workflow/internal/blobstore"
+	"zebra-workflow/internal/codec"
 	logger "zebra-workflow/internal/log"
+	"zebra-workflow/internal/monitor"
+	"zebra-workflow/internal/tracing"
+	"zebra-workflow/internal/types"
 
+	commonpb "go.temporal.io/api/common/v1"
+	enums "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
 
 	"github.com/zeromicro/go-zero/core/conf"
 )
@@ -17,14 +31,24 @@ type Config struct {
 		HostPort         string `json:"hostPort" yaml:"hostPort"`
 		Namespace        string `json:"namespace" yaml:"namespace"`
 		DefaultTaskQueue string `json:"defaultTaskQueue" yaml:"defaultTaskQueue"`
+		// Tenants 租户名 -> namespace / task queue / 凭据，请求通过 X-Tenant 头或 /tenants/:tenant 前缀选择租户
+		Tenants map[string]TenantConfig `json:"tenants,optional" yaml:"tenants"`
+		TLS     TLSConfig               `json:"tls,optional" yaml:"tls"`
+		// Codec payload 加密，server 和 worker 必须使用相同的 keyring
+		Codec codec.Config `json:"codec,optional" yaml:"codec"`
+		// BlobStore 超过阈值的 payload 存入外部存储，server 和 worker 必须使用同一个存储
+		BlobStore blobstore.Config `json:"blobStore,optional" yaml:"blobStore"`
 	} `json:"temporal" yaml:"temporal"`
 }
 
-// ClientWrapper 包装 Temporal client 提供封装方法（start/query/signal）
+// ClientWrapper 包装 Temporal client 提供封装方法（start/query/signal）。
+// 方法按 ctx 中的租户（WithTenant）选择对应 namespace 的 client，未指定租户时使用默认 namespace。
 type ClientWrapper struct {
 	cli          client.Client
 	namespace    string
 	defaultQueue string
+
+	pool *clientPool
 }
 
 // NewClientFromConfig 读取配置并初始化 client
@@ -50,19 +74,76 @@ func NewClientFromConfig(configPath string) (*ClientWrapper, error) {
 		defaultQueue = "zebra-task-queue"
 	}
 
+	payloadCodec, err := codec.New(cfg.Temporal.Codec)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := blobstore.New(cfg.Temporal.BlobStore)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, _, err := cfg.Temporal.BlobStore.GCSettings(); err != nil {
+		return nil, err
+	}
+	if _, err := cfg.Temporal.BlobStore.SentMarkerMaxAge(); err != nil {
+		return nil, err
+	}
+
+	pool := newClientPool(hostPort, defaultQueue, cfg.Temporal.TLS, cfg.Temporal.Tenants)
+	pool.codecOrigins = cfg.Temporal.Codec.AllowedOrigins
+	pool.blobStore = store
+	pool.blobCfg = cfg.Temporal.BlobStore
+	// 编码时从后往前执行：先加密再按大小转存，blob store 中保存的也是密文；解码顺序相反
+	if store != nil {
+		pool.codecs = append(pool.codecs, blobstore.NewCodec(store, cfg.Temporal.BlobStore.Threshold))
+	}
+	if payloadCodec != nil {
+		pool.codecs = append(pool.codecs, payloadCodec)
+	}
+	if len(pool.codecs) > 0 {
+		pool.dataConverter = blobstore.NewDataConverter(pool.codecs...)
+	}
+	cli, err := pool.client(namespace, hostPort, nil, cfg.Temporal.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWrapper{cli: cli, namespace: namespace, defaultQueue: defaultQueue, pool: pool}, nil
+}
+
+// dial 创建连接到 namespace 的 client，所有 client 共享日志、指标、tracing 配置和 data converter
+func dial(hostPort, namespace string, creds client.Credentials, tlsCfg TLSConfig, dc converter.DataConverter) (client.Client, error) {
+	interceptors, err := tracing.ClientInterceptors()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create tracing interceptor: %w", err)
+	}
+	tlsConfig, err := newTLSConfig(tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	cli, err := client.Dial(client.Options{
-		HostPort:  hostPort,
-		Namespace: namespace,
+		HostPort:    hostPort,
+		Namespace:   namespace,
+		Credentials: creds,
+		ConnectionOptions: client.ConnectionOptions{
+			TLS: tlsConfig,
+		},
+		DataConverter:  dc,
+		Logger:         logger.NewTemporalLogger(),
+		MetricsHandler: monitor.TemporalMetricsHandler(),
+		Interceptors:   interceptors,
 	})
 	if err != nil {
-		logger.Sugar.Errorw("unable to create temporal client", "err", err)
-		return nil, fmt.Errorf("unable to create temporal client: %w", err)
+		logger.Sugar.Errorw("unable to create temporal client", "namespace", namespace, "err", err)
+		return nil, fmt.Errorf("unable to create temporal client for namespace %s: %w", namespace, err)
 	}
-	return &ClientWrapper{cli: cli, namespace: namespace, defaultQueue: defaultQueue}, nil
+	return cli, nil
 }
 
+// Close 关闭默认 client 和所有已创建的租户 client
 func (c *ClientWrapper) Close() {
-	c.cli.Close()
+	c.pool.close()
 }
 
 // Client 返回底层 temporal.Client，供外部（worker）使用
@@ -70,21 +151,115 @@ func (c *ClientWrapper) Client() client.Client {
 	return c.cli
 }
 
+// CodecAllowedOrigins 返回允许跨域调用 codec server 的来源（temporal.codec.allowedOrigins）
+func (c *ClientWrapper) CodecAllowedOrigins() []string {
+	return c.pool.codecOrigins
+}
+
+// PayloadCodecs 返回 data converter 使用的全部 codec（blob 转存、加密），供 codec server 使用
+func (c *ClientWrapper) PayloadCodecs() []converter.PayloadCodec {
+	return c.pool.codecs
+}
+
+// BlobStore 返回 payload 转存使用的存储，未启用时返回 nil；活动可用它读取附件等大对象
+func (c *ClientWrapper) BlobStore() blobstore.Store {
+	return c.pool.blobStore
+}
+
+// RunBlobGC 定期删除引用的 workflow 都已关闭超过 BlobMaxAge 的转存 payload、超过 BlobMaxAge 的活动进度
+// 和超过 sentMarkerRetention 的已发送邮件标记，直到 ctx 结束；未启用 blob store 时直接返回
+func (c *ClientWrapper) RunBlobGC(ctx context.Context) {
+	if c.pool.blobStore == nil {
+		return
+	}
+	_, _, interval, _ := c.pool.blobCfg.GCSettings()
+	markerAge, _ := c.pool.blobCfg.SentMarkerMaxAge()
+	blobstore.RunGC(ctx, c.pool.blobStore, interval, func(ctx context.Context) ([]blobstore.GCRule, error) {
+		payloadAge, err := c.BlobMaxAge(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []blobstore.GCRule{
+			{Prefix: blobstore.PayloadPrefix, MaxAge: payloadAge, Closed: c.workflowClosed},
+			{Prefix: blobstore.ProgressPrefix, MaxAge: payloadAge},
+			{Prefix: blobstore.SentMarkerPrefix, MaxAge: markerAge},
+		}, nil
+	})
+}
+
+// workflowClosed 返回 namespace 中 workflow 的关闭时间，供 blob GC 判断转存的 payload 是否仍需保留；
+// namespace 不是默认 namespace 或已配置的租户时返回错误（保留其 blob）
+func (c *ClientWrapper) workflowClosed(ctx context.Context, o blobstore.Owner) (time.Time, bool, error) {
+	w, err := c.forNamespace(o.Namespace)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	desc, err := w.cli.DescribeWorkflowExecution(ctx, o.WorkflowID, "")
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return time.Time{}, true, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	info := desc.GetWorkflowExecutionInfo()
+	if info.GetStatus() == enums.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		return time.Time{}, false, nil
+	}
+	return info.GetCloseTime().AsTime(), true, nil
+}
+
+// BlobMaxAge 返回 blob 的最长保留时间：配置的 retention（为空时取默认 namespace 和所有租户 namespace
+// 的 workflow retention 最大值）加上 gracePeriod
+func (c *ClientWrapper) BlobMaxAge(ctx context.Context) (time.Duration, error) {
+	retention, grace, _, err := c.pool.blobCfg.GCSettings()
+	if err != nil {
+		return 0, err
+	}
+	if retention > 0 {
+		return retention + grace, nil
+	}
+	wrappers := []*ClientWrapper{c}
+	for _, name := range c.Tenants() {
+		w, err := c.ForTenant(name)
+		if err != nil {
+			return 0, err
+		}
+		wrappers = append(wrappers, w)
+	}
+	for _, w := range wrappers {
+		resp, err := w.cli.WorkflowService().DescribeNamespace(ctx, &workflowservice.DescribeNamespaceRequest{Namespace: w.namespace})
+		if err != nil {
+			return 0, fmt.Errorf("describe namespace %s: %w", w.namespace, err)
+		}
+		if ttl := resp.GetConfig().GetWorkflowExecutionRetentionTtl().AsDuration(); ttl > retention {
+			retention = ttl
+		}
+	}
+	return retention + grace, nil
+}
+
 // DefaultQueue 返回默认 task queue 名称
 func (c *ClientWrapper) DefaultQueue() string {
 	return c.defaultQueue
 }
 
 // StartWorkflow 启动 workflow，name + version 决定其实例化工厂（registry 中的工厂）
-func (c *ClientWrapper) StartWorkflow(ctx context.Context, name string, version string, input interface{}) (workflowID string, runID string, err error) {
+func (c *ClientWrapper) StartWorkflow(ctx context.Context, name string, version string, input interface{}, memo map[string]interface{}) (workflowID string, runID string, err error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return "", "", err
+	}
 	// workflowID 可自定义，或直接使用 temporal 生成的
 	workflowID = fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
 	options := client.StartWorkflowOptions{
 		ID:        workflowID,
-		TaskQueue: c.defaultQueue,
+		TaskQueue: tc.defaultQueue,
+		Memo:      memo,
 	}
 	// 可扩展：根据 name/version 设置不同的 retry / timeouts / memo 等
-	we, err := c.cli.ExecuteWorkflow(ctx, options, name, version, input)
+	ctx = blobstore.WithOwner(ctx, blobstore.Owner{Namespace: tc.namespace, WorkflowID: workflowID})
+	we, err := tc.cli.ExecuteWorkflow(ctx, options, name, version, input)
 	if err != nil {
 		return "", "", err
 	}
@@ -93,8 +268,12 @@ func (c *ClientWrapper) StartWorkflow(ctx context.Context, name string, version
 
 // QueryWorkflowStatus 查询当前 workflow 的状态（简单返回基本信息）
 func (c *ClientWrapper) QueryWorkflowStatus(ctx context.Context, workflowID string) (map[string]interface{}, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
 	// 此处我们演示使用 DescribeWorkflowExecution
-	desc, err := c.cli.DescribeWorkflowExecution(ctx, workflowID, "")
+	desc, err := tc.cli.DescribeWorkflowExecution(ctx, workflowID, "")
 	if err != nil {
 		logger.Sugar.Errorw("describe workflow execution failed", "workflowId", workflowID, "err", err)
 		return nil, err
@@ -107,9 +286,305 @@ func (c *ClientWrapper) QueryWorkflowStatus(ctx context.Context, workflowID stri
 
 // SendSignal 向运行中的 workflow 发送 signal
 func (c *ClientWrapper) SendSignal(ctx context.Context, workflowID string, signalName string, payload interface{}) error {
-	if err := c.cli.SignalWorkflow(ctx, workflowID, "", signalName, payload); err != nil {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	ctx = blobstore.WithOwner(ctx, blobstore.Owner{Namespace: tc.namespace, WorkflowID: workflowID})
+	if err := tc.cli.SignalWorkflow(ctx, workflowID, "", signalName, payload); err != nil {
 		logger.Sugar.Errorw("signal workflow failed", "workflowId", workflowID, "err", err)
 		return err
 	}
 	return nil
 }
+
+// GetWorkflowResult 返回 workflow 的状态；已结束的 workflow 同时返回结果或失败信息
+func (c *ClientWrapper) GetWorkflowResult(ctx context.Context, workflowID string) (*types.ResultResp, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := tc.cli.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		logger.Sugar.Errorw("describe workflow execution failed", "workflowId", workflowID, "err", err)
+		return nil, err
+	}
+	info := desc.GetWorkflowExecutionInfo()
+	resp := &types.ResultResp{
+		WorkflowID: workflowID,
+		RunID:      info.GetExecution().GetRunId(),
+		Status:     info.GetStatus().String(),
+	}
+	if info.GetStatus() == enums.WORKFLOW_EXECUTION_STATUS_RUNNING {
+		return resp, nil
+	}
+	var result interface{}
+	if err := tc.cli.GetWorkflow(ctx, workflowID, resp.RunID).Get(ctx, &result); err != nil {
+		resp.Error = err.Error()
+		return resp, nil
+	}
+	resp.Result = result
+	return resp, nil
+}
+
+// CancelWorkflow 请求取消运行中的 workflow
+func (c *ClientWrapper) CancelWorkflow(ctx context.Context, workflowID string) error {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	if err := tc.cli.CancelWorkflow(ctx, workflowID, ""); err != nil {
+		logger.Sugar.Errorw("cancel workflow failed", "workflowId", workflowID, "err", err)
+		return err
+	}
+	return nil
+}
+
+// TerminateWorkflow 强制终止 workflow（不执行清理逻辑）
+func (c *ClientWrapper) TerminateWorkflow(ctx context.Context, workflowID string, reason string) error {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	if err := tc.cli.TerminateWorkflow(ctx, workflowID, "", reason); err != nil {
+		logger.Sugar.Errorw("terminate workflow failed", "workflowId", workflowID, "err", err)
+		return err
+	}
+	return nil
+}
+
+// ResetWorkflow 把 workflow 最新一次 run 重置到 eventID（WorkflowTaskCompleted 事件）之后重新执行；
+// eventID 为 0 时按 resetType（first / last，默认 last）选择 workflow task。返回新 run 的 ID
+func (c *ClientWrapper) ResetWorkflow(ctx context.Context, workflowID string, resetType string, eventID int64, reason string) (string, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return "", err
+	}
+	desc, err := tc.cli.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		return "", err
+	}
+	runID := desc.GetWorkflowExecutionInfo().GetExecution().GetRunId()
+	if eventID == 0 {
+		if eventID, err = tc.workflowTaskEvent(ctx, workflowID, runID, resetType); err != nil {
+			return "", err
+		}
+	}
+	resp, err := tc.cli.ResetWorkflowExecution(ctx, &workflowservice.ResetWorkflowExecutionRequest{
+		Namespace:                 tc.namespace,
+		WorkflowExecution:         &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+		Reason:                    reason,
+		WorkflowTaskFinishEventId: eventID,
+		RequestId:                 requestID(),
+	})
+	if err != nil {
+		logger.Sugar.Errorw("reset workflow failed", "workflowId", workflowID, "eventId", eventID, "err", err)
+		return "", err
+	}
+	return resp.GetRunId(), nil
+}
+
+// workflowTaskEvent 返回 run 中第一个或最后一个 WorkflowTaskCompleted 事件的 ID
+func (c *ClientWrapper) workflowTaskEvent(ctx context.Context, workflowID, runID, resetType string) (int64, error) {
+	if resetType != "" && resetType != "first" && resetType != "last" {
+		return 0, fmt.Errorf("unknown reset type %q (first or last)", resetType)
+	}
+	iter := c.cli.GetWorkflowHistory(ctx, workflowID, runID, false, enums.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	var eventID int64
+	for iter.HasNext() {
+		ev, err := iter.Next()
+		if err != nil {
+			return 0, err
+		}
+		if ev.GetEventType() != enums.EVENT_TYPE_WORKFLOW_TASK_COMPLETED {
+			continue
+		}
+		eventID = ev.GetEventId()
+		if resetType == "first" {
+			break
+		}
+	}
+	if eventID == 0 {
+		return 0, fmt.Errorf("workflow %s has no completed workflow task to reset to", workflowID)
+	}
+	return eventID, nil
+}
+
+// requestID 重置请求的幂等 ID
+func requestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WorkflowType 返回 workflow 的类型名（用于按 workflow 名称授权）
+func (c *ClientWrapper) WorkflowType(ctx context.Context, workflowID string) (string, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return "", err
+	}
+	desc, err := tc.cli.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		logger.Sugar.Errorw("describe workflow execution failed", "workflowId", workflowID, "err", err)
+		return "", err
+	}
+	return desc.GetWorkflowExecutionInfo().GetType().GetName(), nil
+}
+
+// ListWorkflows 按 visibility query 分页列出 workflow（query 为空时列出全部）
+func (c *ClientWrapper) ListWorkflows(ctx context.Context, query string, pageSize int32, pageToken []byte) (*types.ListResp, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := tc.cli.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace:     tc.namespace,
+		PageSize:      pageSize,
+		NextPageToken: pageToken,
+		Query:         query,
+	})
+	if err != nil {
+		logger.Sugar.Errorw("list workflow executions failed", "query", query, "err", err)
+		return nil, err
+	}
+	out := &types.ListResp{Executions: make([]types.ExecutionSummary, 0, len(resp.GetExecutions()))}
+	for _, e := range resp.GetExecutions() {
+		s := types.ExecutionSummary{
+			WorkflowID: e.GetExecution().GetWorkflowId(),
+			RunID:      e.GetExecution().GetRunId(),
+			Type:       e.GetType().GetName(),
+			Status:     e.GetStatus().String(),
+		}
+		if e.GetStartTime() != nil {
+			s.StartTime = e.GetStartTime().AsTime().Format(time.RFC3339)
+		}
+		if e.GetCloseTime() != nil {
+			s.CloseTime = e.GetCloseTime().AsTime().Format(time.RFC3339)
+		}
+		if p, ok := e.GetMemo().GetFields()["startedBy"]; ok {
+			_ = tc.pool.dataConverter.FromPayload(p, &s.StartedBy)
+		}
+		out.Executions = append(out.Executions, s)
+	}
+	out.NextPageToken = resp.GetNextPageToken()
+	return out, nil
+}
+
+// CreateSchedule 创建定时启动 workflow 的 schedule，参数与 StartWorkflow 相同
+func (c *ClientWrapper) CreateSchedule(ctx context.Context, req *types.ScheduleReq, memo map[string]interface{}) error {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	spec := client.ScheduleSpec{CronExpressions: req.Cron}
+	if req.Interval != "" {
+		every, err := time.ParseDuration(req.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid schedule interval %q: %w", req.Interval, err)
+		}
+		spec.Intervals = []client.ScheduleIntervalSpec{{Every: every}}
+	}
+	_, err = tc.cli.ScheduleClient().Create(ctx, client.ScheduleOptions{
+		ID:   req.ID,
+		Spec: spec,
+		Action: &client.ScheduleWorkflowAction{
+			ID:        req.Name,
+			Workflow:  req.Name,
+			Args:      []interface{}{req.Version, req.Input},
+			TaskQueue: tc.defaultQueue,
+			Memo:      memo,
+		},
+		Paused: req.Paused,
+		Note:   req.Note,
+	})
+	if err != nil {
+		logger.Sugar.Errorw("create schedule failed", "scheduleId", req.ID, "err", err)
+		return err
+	}
+	return nil
+}
+
+// ListSchedules 遍历当前 namespace 下全部 schedule，只返回 keep 接受其 workflow 类型的 schedule
+func (c *ClientWrapper) ListSchedules(ctx context.Context, keep func(workflowType string) bool) ([]types.ScheduleSummary, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := tc.cli.ScheduleClient().List(ctx, client.ScheduleListOptions{})
+	if err != nil {
+		logger.Sugar.Errorw("list schedules failed", "err", err)
+		return nil, err
+	}
+	out := make([]types.ScheduleSummary, 0)
+	for iter.HasNext() {
+		e, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !keep(e.WorkflowType.Name) {
+			continue
+		}
+		s := types.ScheduleSummary{
+			ID:       e.ID,
+			Workflow: e.WorkflowType.Name,
+			Paused:   e.Paused,
+			Note:     e.Note,
+		}
+		if e.Spec != nil {
+			s.Cron = e.Spec.CronExpressions
+		}
+		if len(e.NextActionTimes) > 0 {
+			s.NextRun = e.NextActionTimes[0].Format(time.RFC3339)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// ScheduleWorkflowType 返回 schedule 启动的 workflow 类型名
+func (c *ClientWrapper) ScheduleWorkflowType(ctx context.Context, scheduleID string) (string, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return "", err
+	}
+	desc, err := tc.cli.ScheduleClient().GetHandle(ctx, scheduleID).Describe(ctx)
+	if err != nil {
+		logger.Sugar.Errorw("describe schedule failed", "scheduleId", scheduleID, "err", err)
+		return "", err
+	}
+	action, ok := desc.Schedule.Action.(*client.ScheduleWorkflowAction)
+	if !ok {
+		return "", fmt.Errorf("schedule %s does not start a workflow", scheduleID)
+	}
+	name, ok := action.Workflow.(string)
+	if !ok {
+		return "", fmt.Errorf("schedule %s: unexpected workflow type %T", scheduleID, action.Workflow)
+	}
+	return name, nil
+}
+
+// ScheduleAction 对 schedule 执行 delete / pause / unpause / trigger
+func (c *ClientWrapper) ScheduleAction(ctx context.Context, scheduleID string, action string, note string) error {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return err
+	}
+	h := tc.cli.ScheduleClient().GetHandle(ctx, scheduleID)
+	switch action {
+	case "delete":
+		err = h.Delete(ctx)
+	case "pause":
+		err = h.Pause(ctx, client.SchedulePauseOptions{Note: note})
+	case "unpause":
+		err = h.Unpause(ctx, client.ScheduleUnpauseOptions{Note: note})
+	case "trigger":
+		err = h.Trigger(ctx, client.ScheduleTriggerOptions{})
+	default:
+		return fmt.Errorf("unknown schedule action %q", action)
+	}
+	if err != nil {
+		logger.Sugar.Errorw("schedule action failed", "scheduleId", scheduleID, "action", action, "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"golang.org/x/sync/singleflight"
+
+	"zebra-workflow/internal/blobstore"
+	logger "zebra-workflow/internal/log"
+)
+
+// ErrUnknownTenant 请求的租户未在 temporal.tenants 中配置
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// TenantConfig 租户对应的 Temporal namespace、task queue 和凭据。
+// 同一 namespace 只创建一个 client，凭据以第一个使用该 namespace 的租户为准。
+type TenantConfig struct {
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// TaskQueue 为空时使用 temporal.defaultTaskQueue
+	TaskQueue string `json:"taskQueue,optional" yaml:"taskQueue"`
+	// HostPort 为空时使用 temporal.hostPort（如 Temporal Cloud 每个 namespace 有单独的 endpoint）
+	HostPort string `json:"hostPort,optional" yaml:"hostPort"`
+	// APIKeyEnv 保存 Temporal Cloud API key 的环境变量名，key 本身不写入配置文件
+	APIKeyEnv string `json:"apiKeyEnv,optional" yaml:"apiKeyEnv"`
+	// TLS 为空时使用 temporal.tls
+	TLS *TLSConfig `json:"tls,optional" yaml:"tls"`
+}
+
+type tenantKey struct{}
+
+// WithTenant 把租户名放入 context，ClientWrapper 的方法据此选择 namespace
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext 返回 context 中的租户名，未指定时为空
+func TenantFromContext(ctx context.Context) string {
+	t, _ := ctx.Value(tenantKey{}).(string)
+	return t
+}
+
+// clientPool 每个 namespace 一个 client，租户 client 在第一次使用时创建
+type clientPool struct {
+	hostPort     string
+	defaultQueue string
+	tls          TLSConfig
+	tenants      map[string]TenantConfig
+	// blobStore 为 nil 表示未启用；codecs 为 data converter 使用的 codec 链（blob 转存、加密），dataConverter 供所有 client 共享
+	codecOrigins  []string
+	blobStore     blobstore.Store
+	blobCfg       blobstore.Config
+	codecs        []converter.PayloadCodec
+	dataConverter converter.DataConverter
+
+	// dials 合并同一 namespace 的并发 dial；dial 不持有 mu，避免一个不可达的 endpoint 阻塞其他租户
+	dials    singleflight.Group
+	mu       sync.Mutex
+	clients  map[string]client.Client
+	wrappers map[string]*ClientWrapper
+}
+
+func newClientPool(hostPort, defaultQueue string, tlsCfg TLSConfig, tenants map[string]TenantConfig) *clientPool {
+	watchTLSFiles(tlsCfg)
+	for _, t := range tenants {
+		if t.TLS != nil {
+			watchTLSFiles(*t.TLS)
+		}
+	}
+	return &clientPool{
+		hostPort:      hostPort,
+		defaultQueue:  defaultQueue,
+		tls:           tlsCfg,
+		tenants:       tenants,
+		dataConverter: converter.GetDefaultDataConverter(),
+		clients:       make(map[string]client.Client),
+		wrappers:      make(map[string]*ClientWrapper),
+	}
+}
+
+// client 返回 namespace 对应的 client，不存在时创建。调用方不能持有 mu
+func (p *clientPool) client(namespace, hostPort string, creds client.Credentials, tlsCfg TLSConfig) (client.Client, error) {
+	if cli, ok := p.lookup(namespace); ok {
+		return cli, nil
+	}
+	v, err, _ := p.dials.Do(namespace, func() (interface{}, error) {
+		if cli, ok := p.lookup(namespace); ok {
+			return cli, nil
+		}
+		cli, err := dial(hostPort, namespace, creds, tlsCfg, p.dataConverter)
+		if err != nil {
+			return nil, err
+		}
+		p.mu.Lock()
+		p.clients[namespace] = cli
+		p.mu.Unlock()
+		logger.Sugar.Infow("temporal client created", "namespace", namespace, "hostPort", hostPort)
+		return cli, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(client.Client), nil
+}
+
+func (p *clientPool) lookup(namespace string) (client.Client, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cli, ok := p.clients[namespace]
+	return cli, ok
+}
+
+func (p *clientPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for ns, cli := range p.clients {
+		cli.Close()
+		delete(p.clients, ns)
+	}
+}
+
+// Tenants 返回已配置的租户名（按名称排序）
+func (c *ClientWrapper) Tenants() []string {
+	names := make([]string, 0, len(c.pool.tenants))
+	for name := range c.pool.tenants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasTenant 租户是否已配置
+func (c *ClientWrapper) HasTenant(tenant string) bool {
+	_, ok := c.pool.tenants[tenant]
+	return ok
+}
+
+// ForTenant 返回租户对应的 ClientWrapper（共享同一个 pool），第一次调用时创建该 namespace 的 client
+func (c *ClientWrapper) ForTenant(tenant string) (*ClientWrapper, error) {
+	cfg, ok := c.pool.tenants[tenant]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTenant, tenant)
+	}
+
+	c.pool.mu.Lock()
+	w, ok := c.pool.wrappers[tenant]
+	c.pool.mu.Unlock()
+	if ok {
+		return w, nil
+	}
+	if cfg.Namespace == "" {
+		return nil, fmt.Errorf("tenant %s: namespace is required", tenant)
+	}
+	hostPort := cfg.HostPort
+	if hostPort == "" {
+		hostPort = c.pool.hostPort
+	}
+	var creds client.Credentials
+	if cfg.APIKeyEnv != "" {
+		key := os.Getenv(cfg.APIKeyEnv)
+		if key == "" {
+			return nil, fmt.Errorf("tenant %s: environment variable %s is empty", tenant, cfg.APIKeyEnv)
+		}
+		creds = client.NewAPIKeyStaticCredentials(key)
+	}
+	tlsCfg := c.pool.tls
+	if cfg.TLS != nil {
+		tlsCfg = *cfg.TLS
+	}
+	cli, err := c.pool.client(cfg.Namespace, hostPort, creds, tlsCfg)
+	if err != nil {
+		return nil, err
+	}
+	queue := cfg.TaskQueue
+	if queue == "" {
+		queue = c.pool.defaultQueue
+	}
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	// 并发的第一次调用可能已经创建了 wrapper，保留先存入的那个
+	if w, ok := c.pool.wrappers[tenant]; ok {
+		return w, nil
+	}
+	w = &ClientWrapper{cli: cli, namespace: cfg.Namespace, defaultQueue: queue, pool: c.pool}
+	c.pool.wrappers[tenant] = w
+	return w, nil
+}
+
+// forNamespace 返回 namespace 对应的 ClientWrapper（默认 namespace 或某个租户的 namespace）
+func (c *ClientWrapper) forNamespace(namespace string) (*ClientWrapper, error) {
+	if namespace == c.namespace {
+		return c, nil
+	}
+	for _, name := range c.Tenants() {
+		if c.pool.tenants[name].Namespace == namespace {
+			return c.ForTenant(name)
+		}
+	}
+	return nil, fmt.Errorf("namespace %s is not configured", namespace)
+}
+
+// scoped 返回 ctx 中租户对应的 ClientWrapper，未指定租户时返回 c 本身
+func (c *ClientWrapper) scoped(ctx context.Context) (*ClientWrapper, error) {
+	tenant := TenantFromContext(ctx)
+	if tenant == "" {
+		return c, nil
+	}
+	return c.ForTenant(tenant)
+}
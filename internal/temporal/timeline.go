@@ -0,0 +1,165 @@
+package temporal
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	enums "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	dslpkg "zebra-workflow/internal/dsl"
+	logger "zebra-workflow/internal/log"
+	"zebra-workflow/internal/types"
+)
+
+// GetWorkflowHistory 读取 workflow 最新一次 run 的完整事件历史
+func (c *ClientWrapper) GetWorkflowHistory(ctx context.Context, workflowID string) ([]*historypb.HistoryEvent, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iter := tc.cli.GetWorkflowHistory(ctx, workflowID, "", false, enums.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	var events []*historypb.HistoryEvent
+	for iter.HasNext() {
+		ev, err := iter.Next()
+		if err != nil {
+			logger.Sugar.Errorw("get workflow history failed", "workflowId", workflowID, "err", err)
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// GetTimeline 把 history 中每个活动的 scheduled / started / closed 事件合并为一个步骤，
+// DSL 活动的 ActivityID 即语句路径（如 root/seq[0]），据此映射回 DSL 语句。
+// 仍在运行（或等待重试）的活动从 DescribeWorkflowExecution 的 PendingActivities 补充 attempt 和最近一次失败。
+func (c *ClientWrapper) GetTimeline(ctx context.Context, workflowID string) (*types.TimelineResp, error) {
+	tc, err := c.scoped(ctx)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := tc.cli.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		logger.Sugar.Errorw("describe workflow execution failed", "workflowId", workflowID, "err", err)
+		return nil, err
+	}
+	events, err := c.GetWorkflowHistory(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := desc.GetWorkflowExecutionInfo()
+	resp := &types.TimelineResp{
+		WorkflowID: workflowID,
+		RunID:      info.GetExecution().GetRunId(),
+		Status:     info.GetStatus().String(),
+		Steps:      make([]*types.TimelineStep, 0),
+	}
+	resp.StartTime = formatTime(info.GetStartTime())
+	resp.CloseTime = formatTime(info.GetCloseTime())
+
+	byScheduledID := make(map[int64]*types.TimelineStep)
+	byActivityID := make(map[string]*types.TimelineStep)
+	for _, ev := range events {
+		switch ev.GetEventType() {
+		case enums.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED:
+			attrs := ev.GetActivityTaskScheduledEventAttributes()
+			step := &types.TimelineStep{
+				Statement:     statementOf(attrs.GetActivityId()),
+				Activity:      attrs.GetActivityType().GetName(),
+				ActivityID:    attrs.GetActivityId(),
+				Status:        "scheduled",
+				ScheduledTime: formatTime(ev.GetEventTime()),
+				Attempt:       1,
+			}
+			byScheduledID[ev.GetEventId()] = step
+			byActivityID[step.ActivityID] = step
+			resp.Steps = append(resp.Steps, step)
+		case enums.EVENT_TYPE_ACTIVITY_TASK_STARTED:
+			attrs := ev.GetActivityTaskStartedEventAttributes()
+			if step, ok := byScheduledID[attrs.GetScheduledEventId()]; ok {
+				step.Status = "running"
+				step.StartedTime = formatTime(ev.GetEventTime())
+				step.Attempt = attrs.GetAttempt()
+				if f := attrs.GetLastFailure(); f != nil {
+					step.Failure = f.GetMessage()
+				}
+			}
+		case enums.EVENT_TYPE_ACTIVITY_TASK_COMPLETED:
+			closeStep(byScheduledID[ev.GetActivityTaskCompletedEventAttributes().GetScheduledEventId()], ev, "completed", "")
+		case enums.EVENT_TYPE_ACTIVITY_TASK_FAILED:
+			attrs := ev.GetActivityTaskFailedEventAttributes()
+			closeStep(byScheduledID[attrs.GetScheduledEventId()], ev, "failed", attrs.GetFailure().GetMessage())
+		case enums.EVENT_TYPE_ACTIVITY_TASK_TIMED_OUT:
+			attrs := ev.GetActivityTaskTimedOutEventAttributes()
+			closeStep(byScheduledID[attrs.GetScheduledEventId()], ev, "timedOut", attrs.GetFailure().GetMessage())
+		case enums.EVENT_TYPE_ACTIVITY_TASK_CANCELED:
+			closeStep(byScheduledID[ev.GetActivityTaskCanceledEventAttributes().GetScheduledEventId()], ev, "canceled", "")
+		}
+	}
+
+	for _, pa := range desc.GetPendingActivities() {
+		step, ok := byActivityID[pa.GetActivityId()]
+		if !ok {
+			continue
+		}
+		step.Attempt = pa.GetAttempt()
+		if f := pa.GetLastFailure(); f != nil {
+			step.Failure = f.GetMessage()
+		}
+		if pa.GetState() == enums.PENDING_ACTIVITY_STATE_STARTED {
+			step.Status = "running"
+			if t := pa.GetLastStartedTime(); t != nil {
+				step.StartedTime = formatTime(t)
+			}
+		}
+	}
+
+	for _, step := range resp.Steps {
+		step.QueueMs = millisBetween(step.ScheduledTime, step.StartedTime)
+		step.DurationMs = millisBetween(step.StartedTime, step.CloseTime)
+	}
+	return resp, nil
+}
+
+func closeStep(step *types.TimelineStep, ev *historypb.HistoryEvent, status, failure string) {
+	if step == nil {
+		return
+	}
+	step.Status = status
+	step.CloseTime = formatTime(ev.GetEventTime())
+	if failure != "" {
+		step.Failure = failure
+	}
+}
+
+// statementOf 只有 DSL 路径形式的 ActivityID 才对应 DSL 语句（旧版本的执行使用数字 ActivityID）
+func statementOf(activityID string) string {
+	if activityID == dslpkg.RootStatementID || strings.HasPrefix(activityID, dslpkg.RootStatementID+"/") {
+		return activityID
+	}
+	return ""
+}
+
+func formatTime(t *timestamppb.Timestamp) string {
+	if t == nil || (t.GetSeconds() == 0 && t.GetNanos() == 0) {
+		return ""
+	}
+	return t.AsTime().Format(time.RFC3339Nano)
+}
+
+// millisBetween 计算两个 RFC3339 时间之间的毫秒数，任一为空时返回 0
+func millisBetween(from, to string) int64 {
+	if from == "" || to == "" {
+		return 0
+	}
+	f, err1 := time.Parse(time.RFC3339Nano, from)
+	t, err2 := time.Parse(time.RFC3339Nano, to)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return t.Sub(f).Milliseconds()
+}
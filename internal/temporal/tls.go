@@ -0,0 +1,189 @@
+package temporal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	logger "zebra-workflow/internal/log"
+)
+
+// TLSConfig 映射 temporal.tls（租户可在 tenants.<name>.tls 中单独配置）。
+// certFile / keyFile 用于 mTLS，caFile 为空时使用系统根证书。
+type TLSConfig struct {
+	Enabled    bool   `json:"enabled,optional" yaml:"enabled"`
+	CAFile     string `json:"caFile,optional" yaml:"caFile"`
+	CertFile   string `json:"certFile,optional" yaml:"certFile"`
+	KeyFile    string `json:"keyFile,optional" yaml:"keyFile"`
+	ServerName string `json:"serverName,optional" yaml:"serverName"`
+	// InsecureSkipVerify 不校验服务端证书，仅用于开发环境
+	InsecureSkipVerify bool `json:"insecureSkipVerify,optional" yaml:"insecureSkipVerify"`
+}
+
+// tlsSource 保存当前的客户端证书和 CA，证书文件变化后由 ReloadTLS 重新加载。
+// 新证书用于之后建立的连接，已建立的连接不受影响。
+type tlsSource struct {
+	cfg TLSConfig
+
+	mu    sync.RWMutex
+	cert  *tls.Certificate
+	roots *x509.CertPool
+}
+
+var (
+	tlsMu      sync.Mutex
+	tlsSources []*tlsSource
+	// tlsWatched 配置中出现的所有证书文件（包括尚未创建 client 的租户），供 watcher 监听
+	tlsWatched []string
+)
+
+// watchTLSFiles 登记需要监听的证书文件
+func watchTLSFiles(cfg TLSConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	tlsMu.Lock()
+	defer tlsMu.Unlock()
+	for _, f := range (&tlsSource{cfg: cfg}).files() {
+		if !containsString(tlsWatched, f) {
+			tlsWatched = append(tlsWatched, f)
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// newTLSConfig 按配置加载证书并返回 client 使用的 *tls.Config；未启用 TLS 时返回 nil
+func newTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, errors.New("temporal tls: certFile and keyFile must be set together")
+	}
+	s := &tlsSource{cfg: cfg}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	tlsMu.Lock()
+	tlsSources = append(tlsSources, s)
+	tlsMu.Unlock()
+	return s.tlsConfig(), nil
+}
+
+func (s *tlsSource) reload() error {
+	var cert *tls.Certificate
+	if s.cfg.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(s.cfg.CertFile, s.cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("temporal tls: load client certificate: %w", err)
+		}
+		cert = &c
+	}
+	var roots *x509.CertPool
+	if s.cfg.CAFile != "" {
+		pem, err := os.ReadFile(s.cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("temporal tls: read ca file: %w", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("temporal tls: no certificates found in %s", s.cfg.CAFile)
+		}
+	}
+	s.mu.Lock()
+	s.cert, s.roots = cert, roots
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *tlsSource) tlsConfig() *tls.Config {
+	c := &tls.Config{
+		ServerName: s.cfg.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if s.cfg.CertFile != "" {
+		c.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			return s.cert, nil
+		}
+	}
+	if s.cfg.InsecureSkipVerify {
+		c.InsecureSkipVerify = true
+		return c
+	}
+	if s.cfg.CAFile != "" {
+		// RootCAs 创建后不能替换，改为关闭默认校验，在 VerifyConnection 中用当前 CA 校验
+		c.InsecureSkipVerify = true
+		c.VerifyConnection = s.verify
+	}
+	return c
+}
+
+func (s *tlsSource) verify(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("temporal tls: server presented no certificate")
+	}
+	s.mu.RLock()
+	roots := s.roots
+	s.mu.RUnlock()
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, c := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+func (s *tlsSource) files() []string {
+	var files []string
+	for _, f := range []string{s.cfg.CAFile, s.cfg.CertFile, s.cfg.KeyFile} {
+		if f != "" {
+			files = append(files, filepath.Clean(f))
+		}
+	}
+	return files
+}
+
+// TLSFiles 返回配置中的所有证书文件，供配置 watcher 监听
+func TLSFiles() []string {
+	tlsMu.Lock()
+	defer tlsMu.Unlock()
+	return append([]string(nil), tlsWatched...)
+}
+
+// IsTLSFile 判断 watcher 事件中的文件是否是证书文件
+func IsTLSFile(name string) bool {
+	return containsString(TLSFiles(), filepath.Clean(name))
+}
+
+// ReloadTLS 重新加载所有证书；加载失败时保留原来的证书并记录日志
+func ReloadTLS() {
+	tlsMu.Lock()
+	sources := append([]*tlsSource(nil), tlsSources...)
+	tlsMu.Unlock()
+	for _, s := range sources {
+		if err := s.reload(); err != nil {
+			logger.Sugar.Errorw("reload temporal tls certificates failed, keeping the previous ones", "files", s.files(), "err", err)
+			continue
+		}
+		logger.Sugar.Infow("temporal tls certificates reloaded", "files", s.files())
+	}
+}
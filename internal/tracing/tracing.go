@@ -0,0 +1,176 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/sdk/contrib/opentelemetry"
+	"go.temporal.io/sdk/interceptor"
+
+	logger "zebra-workflow/internal/log"
+)
+
+const tracerName = "zebra-workflow"
+
+// Config 映射 configs/config.yaml 中的 tracing 段
+type Config struct {
+	Enabled bool `yaml:"enabled" json:"enabled,optional"`
+	// Exporter: "otlp"（gRPC）或 "stdout"（本地调试）
+	Exporter    string  `yaml:"exporter" json:"exporter,optional"`
+	Endpoint    string  `yaml:"endpoint" json:"endpoint,optional"`
+	Insecure    bool    `yaml:"insecure" json:"insecure,optional"`
+	ServiceName string  `yaml:"serviceName" json:"serviceName,optional"`
+	SampleRatio float64 `yaml:"sampleRatio" json:"sampleRatio,optional"`
+}
+
+var (
+	enabled bool
+
+	interceptorOnce sync.Once
+	temporalTracing interceptor.Interceptor
+	interceptorErr  error
+)
+
+// Init 按配置安装全局 TracerProvider 和 W3C propagator，返回用于退出时 flush 的 shutdown。
+// 未启用时不做任何事，Middleware / 拦截器均为空操作。
+func Init(ctx context.Context, cfg Config, defaultService string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !cfg.Enabled {
+		return noop, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "", "otlp":
+		opts := []otlptracegrpc.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return noop, fmt.Errorf("unknown tracing exporter %q (otlp or stdout)", cfg.Exporter)
+	}
+	if err != nil {
+		return noop, fmt.Errorf("create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	service := cfg.ServiceName
+	if service == "" {
+		service = defaultService
+	}
+	ratio := cfg.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	enabled = true
+
+	logger.Sugar.Infow("tracing enabled", "exporter", cfg.Exporter, "endpoint", cfg.Endpoint, "service", service)
+	return tp.Shutdown, nil
+}
+
+// Middleware 为路由创建 server span（名称为 "METHOD 路由模板"），并从请求头中提取上游 trace 上下文。
+// span 随 r.Context() 传入 logic 层，再由 Temporal 拦截器传播到 workflow。
+func Middleware(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !enabled {
+			next(w, r)
+			return
+		}
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := otel.Tracer(tracerName).Start(ctx, r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("http.target", r.URL.Path),
+			))
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r.WithContext(ctx))
+		span.SetAttributes(attribute.Int("http.status_code", rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	}
+}
+
+// temporalInterceptor 懒加载 Temporal SDK 的 OpenTelemetry 拦截器（需在 Init 之后调用，才能使用全局 TracerProvider）
+func temporalInterceptor() (interceptor.Interceptor, error) {
+	interceptorOnce.Do(func() {
+		temporalTracing, interceptorErr = opentelemetry.NewTracingInterceptor(opentelemetry.TracerOptions{
+			Tracer: otel.Tracer(tracerName + "/temporal"),
+		})
+	})
+	return temporalTracing, interceptorErr
+}
+
+// clientOnly / workerOnly 把同一个拦截器拆成两半：SDK 会把同时实现 WorkerInterceptor 的 client 拦截器自动用于 worker，
+// 显式分开后 client 与 worker.Options 各装一份，避免 worker 端 span 重复。
+type clientOnly struct{ interceptor.ClientInterceptor }
+
+type workerOnly struct{ interceptor.WorkerInterceptor }
+
+// ClientInterceptors 返回 client.Options.Interceptors，未启用 tracing 时为空
+func ClientInterceptors() ([]interceptor.ClientInterceptor, error) {
+	if !enabled {
+		return nil, nil
+	}
+	i, err := temporalInterceptor()
+	if err != nil {
+		return nil, err
+	}
+	return []interceptor.ClientInterceptor{clientOnly{i}}, nil
+}
+
+// WorkerInterceptors 返回 worker.Options.Interceptors，未启用 tracing 时为空
+func WorkerInterceptors() ([]interceptor.WorkerInterceptor, error) {
+	if !enabled {
+		return nil, nil
+	}
+	i, err := temporalInterceptor()
+	if err != nil {
+		return nil, err
+	}
+	return []interceptor.WorkerInterceptor{workerOnly{i}}, nil
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
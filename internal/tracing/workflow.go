@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+)
+
+// headerKey 与 Temporal OpenTelemetry 拦截器默认的 HeaderKey 一致，保存启动 workflow 时的 span 上下文
+const headerKey = "_tracer-data"
+
+type workflowSpanKey struct{}
+
+// StartWorkflowSpan 在 workflow 代码中创建 span（如 DSL 语句），返回携带该 span 的 workflow.Context 和结束函数。
+// 父 span 为外层语句的 span，最外层取 workflow header 中由 client 拦截器写入的 StartWorkflow span。
+// 重放时不创建 span，span 的起止时间使用 workflow.Now，保证与 history 一致。
+func StartWorkflowSpan(ctx workflow.Context, name string, attrs ...attribute.KeyValue) (workflow.Context, func(error)) {
+	if !enabled || workflow.IsReplaying(ctx) {
+		return ctx, func(error) {}
+	}
+	spanCtx, span := otel.Tracer(tracerName).Start(parentContext(ctx), name,
+		trace.WithTimestamp(workflow.Now(ctx)),
+		trace.WithAttributes(attrs...))
+	ctx = workflow.WithValue(ctx, workflowSpanKey{}, spanCtx)
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End(trace.WithTimestamp(workflow.Now(ctx)))
+	}
+}
+
+func parentContext(ctx workflow.Context) context.Context {
+	if c, ok := ctx.Value(workflowSpanKey{}).(context.Context); ok {
+		return c
+	}
+	payload, ok := interceptor.WorkflowHeader(ctx)[headerKey]
+	if !ok {
+		return context.Background()
+	}
+	var carrier map[string]string
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &carrier); err != nil {
+		return context.Background()
+	}
+	return otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(carrier))
+}
@@ -19,14 +19,183 @@ type SignalReq struct {
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 }
 
+// TerminateReq 终止 workflow 的原因（记录在 history 中）
+type TerminateReq struct {
+	Reason string `json:"reason,optional"`
+}
+
+// ActivityRefReq 定位等待外部完成的活动（AsyncActivity）：taskToken（base64url 编码），或 workflowId + activityId
+type ActivityRefReq struct {
+	TaskToken  string `json:"taskToken,optional"`
+	WorkflowID string `json:"workflowId,optional"`
+	RunID      string `json:"runId,optional"`
+	ActivityID string `json:"activityId,optional"`
+}
+
+// CompleteActivityReq 外部系统完成活动：result 为活动结果；error 不为空时活动失败，canceled 为 true 时活动以取消结束
+// （details 作为取消详情）
+type CompleteActivityReq struct {
+	ActivityRefReq
+	Result   interface{}      `json:"result,optional"`
+	Error    *ActivityFailure `json:"error,optional"`
+	Canceled bool             `json:"canceled,optional"`
+	Details  interface{}      `json:"details,optional"`
+}
+
+// ActivityFailure 外部系统报告的活动失败，type 默认 AsyncActivityFailed，nonRetryable 为 false 时按活动的 RetryPolicy 重试
+type ActivityFailure struct {
+	Message      string      `json:"message"`
+	Type         string      `json:"type,optional"`
+	NonRetryable bool        `json:"nonRetryable,optional"`
+	Details      interface{} `json:"details,optional"`
+}
+
+// HeartbeatActivityReq 外部系统为活动心跳，details 为进度
+type HeartbeatActivityReq struct {
+	ActivityRefReq
+	Details interface{} `json:"details,optional"`
+}
+
+// HeartbeatActivityResp cancelRequested 为 true 时活动已被请求取消，外部系统应停止处理并以 canceled 完成活动
+type HeartbeatActivityResp struct {
+	CancelRequested bool `json:"cancelRequested"`
+}
+
+// ResetReq 把 workflow 重置到某个 workflow task 之后重新执行：eventId 指定 WorkflowTaskCompleted 事件，
+// 为 0 时按 type 选择第一个（first）或最后一个（last，默认）workflow task
+type ResetReq struct {
+	Reason  string `json:"reason,optional"`
+	Type    string `json:"type,optional"`
+	EventID int64  `json:"eventId,optional"`
+}
+
+// ResetResp 重置后新 run 的 ID
+type ResetResp struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+}
+
 // InfoResp / Query 接口的简单响应（可按需扩展）
 type InfoResp struct {
 	HTTPAddr string            `json:"httpAddr"`
 	Temporal map[string]string `json:"temporal"`
 }
 
-type ArticleInfo struct {
-	Title   string `json:"title"`
-	Time    string `json:"time"`
-	Content string `json:"content"`
+// SimulateReq 离线模拟 DSL 的请求体：input 与启动 DSLWorkflow 时相同，variables 覆盖 DSL 中的 Variables。
+// name 为正在编写的 DSL 定义名，按 define 权限授权（为空时需要全部定义的权限）
+type SimulateReq struct {
+	Name      string                 `json:"name,optional"`
+	Input     map[string]interface{} `json:"input"`
+	Variables map[string]string      `json:"variables,optional"`
+	Mocks     []ActivityMock         `json:"mocks,optional"`
+}
+
+// ActivityMock 指定某个活动第 call 次调用的返回（call 为 0 表示默认返回）
+type ActivityMock struct {
+	Activity     string                 `json:"activity"`
+	Call         int                    `json:"call,optional"`
+	Output       map[string]interface{} `json:"output,optional"`
+	Error        string                 `json:"error,optional"`
+	NonRetryable bool                   `json:"nonRetryable,optional"`
+}
+
+// ResultResp workflow 结果：运行中只返回 status，结束后返回 result 或 error
+type ResultResp struct {
+	WorkflowID string      `json:"workflowId"`
+	RunID      string      `json:"runId"`
+	Status     string      `json:"status"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// ListReq 列出 workflow 的查询参数，query 为 Temporal visibility 查询语句
+type ListReq struct {
+	Query         string `form:"query,optional"`
+	PageSize      int32  `form:"pageSize,optional"`
+	NextPageToken string `form:"nextPageToken,optional"`
+}
+
+// ListResp 列出 workflow 的响应，nextPageToken 为空表示没有下一页
+type ListResp struct {
+	Executions    []ExecutionSummary `json:"executions"`
+	NextPageToken []byte             `json:"nextPageToken,omitempty"`
+}
+
+type ExecutionSummary struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+	StartTime  string `json:"startTime,omitempty"`
+	CloseTime  string `json:"closeTime,omitempty"`
+	StartedBy  string `json:"startedBy,omitempty"`
+}
+
+// ValidateReq DSL 静态检查请求，input 与启动 DSLWorkflow 时相同，name 与 SimulateReq 相同
+type ValidateReq struct {
+	Name  string                 `json:"name,optional"`
+	Input map[string]interface{} `json:"input"`
+}
+
+// ValidateResp DSL 静态检查结果
+type ValidateResp struct {
+	Valid    bool      `json:"valid"`
+	Problems []Problem `json:"problems,omitempty"`
+}
+
+// Problem 定位到语句路径（如 root/seq[1]）的单个 DSL 问题
+type Problem struct {
+	Statement string `json:"statement"`
+	Message   string `json:"message"`
+}
+
+// ScheduleReq 创建 schedule：按 cron 或 interval（如 "1h"）定时启动 name/version/input 对应的 workflow
+type ScheduleReq struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	Version  string                 `json:"version,optional"`
+	Input    map[string]interface{} `json:"input,optional"`
+	Cron     []string               `json:"cron,optional"`
+	Interval string                 `json:"interval,optional"`
+	Paused   bool                   `json:"paused,optional"`
+	Note     string                 `json:"note,optional"`
+}
+
+// ScheduleActionReq pause / unpause 时附带的说明
+type ScheduleActionReq struct {
+	Note string `json:"note,optional"`
+}
+
+type ScheduleSummary struct {
+	ID       string   `json:"id"`
+	Workflow string   `json:"workflow"`
+	Cron     []string `json:"cron,omitempty"`
+	Paused   bool     `json:"paused"`
+	Note     string   `json:"note,omitempty"`
+	NextRun  string   `json:"nextRun,omitempty"`
+}
+
+// TimelineResp workflow 执行时间线：每个活动一个步骤，DSL 活动带有对应的语句路径
+type TimelineResp struct {
+	WorkflowID string          `json:"workflowId"`
+	RunID      string          `json:"runId"`
+	Status     string          `json:"status"`
+	StartTime  string          `json:"startTime,omitempty"`
+	CloseTime  string          `json:"closeTime,omitempty"`
+	Steps      []*TimelineStep `json:"steps"`
+}
+
+// TimelineStep 单个活动的时间信息；queueMs 为 scheduled -> started，durationMs 为 started -> closed
+type TimelineStep struct {
+	Statement     string `json:"statement,omitempty"`
+	Activity      string `json:"activity"`
+	ActivityID    string `json:"activityId"`
+	Status        string `json:"status"`
+	ScheduledTime string `json:"scheduledTime,omitempty"`
+	StartedTime   string `json:"startedTime,omitempty"`
+	CloseTime     string `json:"closeTime,omitempty"`
+	QueueMs       int64  `json:"queueMs"`
+	DurationMs    int64  `json:"durationMs"`
+	Attempt       int32  `json:"attempt"`
+	Failure       string `json:"failure,omitempty"`
 }
@@ -3,83 +3,87 @@ package workflow
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	dslpkg "zebra-workflow/internal/dsl"
-	"zebra-workflow/internal/log"
 
 	"go.temporal.io/sdk/workflow"
 )
 
+// DSLWorkflowName DSL workflow 注册的名称，input 即 DSL 本身
+const DSLWorkflowName = "DSLWorkflow"
+
 // DSLWorkflowWrapper 拆装参数并调用 samples 的 SimpleDSLWorkflow。
 // 支持多种输入形态：
 // 1. 直接传入 dsl.Workflow 的 JSON（推荐）
 // 2. 将 DSL 包在 input["input"]（或 "Input"）里（兼容某些前端）
 func init() {
 	Register(&RegisteredWorkflow{
-		Name:    "DSLWorkflow",
+		Name:    DSLWorkflowName,
 		Version: "v1",
 		Factory: func() interface{} { return DSLWorkflowWrapper },
 		Default: true,
+		// DSL 的 Variables / 结果的 schema 写在 DSL 中（VariablesSchema / OutputSchema）
+		CheckInput: func(input map[string]interface{}) error {
+			w, err := ParseDSLInput(input)
+			if err != nil {
+				return err
+			}
+			return w.CheckSchemas()
+		},
 	})
 }
 
-func DSLWorkflowWrapper(ctx workflow.Context, version string, input map[string]interface{}) error {
+func DSLWorkflowWrapper(ctx workflow.Context, version string, input map[string]interface{}) (interface{}, error) {
+	// workflow.GetLogger 重放时不输出，并附带 workflowId / runId 等字段
+	logger := workflow.GetLogger(ctx)
+	dslWorkflow, err := ParseDSLInput(input)
+	if err != nil {
+		logger.Error("dsl adapter: unable to parse workflow input into DSL", "inputKeys", keysOf(input), "err", err)
+		return nil, err
+	}
+	logger.Info("dsl adapter: input parsed and will be executed", "version", version)
+	return dslpkg.SimpleDSLWorkflow(ctx, dslWorkflow)
+}
+
+// ParseDSLInput 把 HTTP / workflow 的 input 解析为 dsl.Workflow。
+// 优先按 dsl.Workflow 直接解析，内容为空时再尝试嵌套的 "input" / "Input" 键。
+func ParseDSLInput(input map[string]interface{}) (dslpkg.Workflow, error) {
 	// 尝试直接把 input 解析为 dsl.Workflow（最常见）
 	var dslWorkflow dslpkg.Workflow
 	b, err := json.Marshal(input)
 	if err != nil {
-		log.Sugar.Errorw("dsl adapter: marshal input failed", "err", err)
-		return err
+		return dslWorkflow, err
 	}
 	if err := json.Unmarshal(b, &dslWorkflow); err == nil {
 		// 判断是否解析出至少一部分有意义的数据（Root 不为空或 Variables 不空）
 		if dslWorkflow.Root.Activity != nil || dslWorkflow.Root.Sequence != nil || dslWorkflow.Root.Parallel != nil || len(dslWorkflow.Variables) > 0 {
-			log.Sugar.Infow("dsl adapter: input parsed as direct Workflow", "version", version)
-			_, err = dslpkg.SimpleDSLWorkflow(ctx, dslWorkflow)
-			return err
+			return dslWorkflow, nil
 		}
 	}
 
-	// 若直接解析后内容为空，则尝试解包常见的嵌套键 "input" / "Input"
-	if nestedRaw, ok := input["input"]; ok {
-		if nestedMap, ok2 := nestedRaw.(map[string]interface{}); ok2 {
-			// marshal nested and unmarshal
-			b2, merr := json.Marshal(nestedMap)
-			if merr != nil {
-				log.Sugar.Errorw("dsl adapter: marshal nested input failed", "err", merr)
-				return merr
-			}
-			if err := json.Unmarshal(b2, &dslWorkflow); err != nil {
-				log.Sugar.Errorw("dsl adapter: unmarshal nested input failed", "err", err)
-				return err
-			}
-			log.Sugar.Infow("dsl adapter: nested input parsed and will be executed", "version", version)
-			_, err = dslpkg.SimpleDSLWorkflow(ctx, dslWorkflow)
-			return err
+	// 若直接解析后内容为空，则尝试解包常见的嵌套键 "input" / "Input"（兼容不同客户端）
+	for _, key := range []string{"input", "Input"} {
+		nestedRaw, ok := input[key]
+		if !ok {
+			continue
 		}
-	}
-
-	// 也尝试 "Input"（大写）键以兼容不同客户端
-	if nestedRaw, ok := input["Input"]; ok {
-		if nestedMap, ok2 := nestedRaw.(map[string]interface{}); ok2 {
-			b2, merr := json.Marshal(nestedMap)
-			if merr != nil {
-				log.Sugar.Errorw("dsl adapter: marshal Input failed", "err", merr)
-				return merr
-			}
-			if err := json.Unmarshal(b2, &dslWorkflow); err != nil {
-				log.Sugar.Errorw("dsl adapter: unmarshal Input failed", "err", err)
-				return err
-			}
-			log.Sugar.Infow("dsl adapter: Input parsed and will be executed", "version", version)
-			_, err = dslpkg.SimpleDSLWorkflow(ctx, dslWorkflow)
-			return err
+		nestedMap, ok := nestedRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		b2, err := json.Marshal(nestedMap)
+		if err != nil {
+			return dslWorkflow, fmt.Errorf("marshal nested %q: %w", key, err)
+		}
+		if err := json.Unmarshal(b2, &dslWorkflow); err != nil {
+			return dslWorkflow, fmt.Errorf("unmarshal nested %q: %w", key, err)
 		}
+		return dslWorkflow, nil
 	}
 
 	// 如果都不能解析出合理 DSL，返回错误提示
-	log.Sugar.Errorw("dsl adapter: unable to parse workflow input into DSL", "inputKeys", keysOf(input))
-	return errors.New("invalid dsl input: expected dsl.Workflow structure or nested 'input' object")
+	return dslWorkflow, errors.New("invalid dsl input: expected dsl.Workflow structure or nested 'input' object")
 }
 
 // keysOf 辅助函数：返回 map 的 key 列表（用于日志）
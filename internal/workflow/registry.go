@@ -1,6 +1,12 @@
 package workflow
 
-import "go.temporal.io/sdk/workflow"
+import (
+	"encoding/json"
+
+	"go.temporal.io/sdk/workflow"
+
+	"zebra-workflow/internal/schema"
+)
 
 // 定义 workflow 注册信息
 
@@ -11,6 +17,13 @@ type RegisteredWorkflow struct {
 	Version string
 	Factory WorkflowFactory
 	Default bool
+
+	// InputSchema / OutputSchema 可选的 JSON Schema：启动时按 InputSchema 检查 input，
+	// worker 按 OutputSchema 检查 workflow 的返回值（见 NewSchemaInterceptor）
+	InputSchema  json.RawMessage
+	OutputSchema json.RawMessage
+	// CheckInput 可选的额外检查，如 DSLWorkflow 按 DSL 自带的 VariablesSchema 检查 Variables
+	CheckInput func(input map[string]interface{}) error
 }
 
 var registry = make([]*RegisteredWorkflow, 0)
@@ -23,6 +36,31 @@ func ListRegistered() []*RegisteredWorkflow {
 	return registry
 }
 
+// Lookup 按名称查找已注册的 workflow
+func Lookup(name string) (*RegisteredWorkflow, bool) {
+	for _, r := range registry {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// ValidateInput 启动前检查 input：InputSchema 不符合时返回 *schema.ValidationError。未注册的 workflow 不检查
+func ValidateInput(name string, input map[string]interface{}) error {
+	r, ok := Lookup(name)
+	if !ok {
+		return nil
+	}
+	if err := schema.Validate(r.InputSchema, "input", input); err != nil {
+		return err
+	}
+	if r.CheckInput != nil {
+		return r.CheckInput(input)
+	}
+	return nil
+}
+
 func GetRegisterOptions(r *RegisteredWorkflow) workflow.RegisterOptions {
 	// 可以根据 version 设置不同的 options，比方说 name 带 version 后缀
 	return workflow.RegisterOptions{Name: r.Name}
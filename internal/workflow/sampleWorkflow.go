@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"encoding/json"
 	"time"
 
 	"zebra-workflow/internal/activity"
@@ -16,6 +17,8 @@ func init() {
 		Version: "v1",
 		Factory: func() interface{} { return SampleWorkflow },
 		Default: true,
+		// input 原样传给 DoSomethingActivity
+		InputSchema: json.RawMessage(`{"type": "object"}`),
 	})
 }
 
@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+
+	"zebra-workflow/internal/schema"
+)
+
+// NewSchemaInterceptor 返回 worker interceptor：注册了 OutputSchema 的 workflow 返回值不符合时 workflow 失败。
+// schema 在创建时编译，无效的 schema 返回错误
+func NewSchemaInterceptor() (interceptor.WorkerInterceptor, error) {
+	schemas := make(map[string]*schema.Schema)
+	for _, r := range ListRegistered() {
+		if len(r.OutputSchema) == 0 {
+			continue
+		}
+		s, err := schema.Compile(r.OutputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("workflow %s output schema: %w", r.Name, err)
+		}
+		schemas[r.Name] = s
+	}
+	return &schemaInterceptor{schemas: schemas}, nil
+}
+
+type schemaInterceptor struct {
+	interceptor.WorkerInterceptorBase
+	schemas map[string]*schema.Schema
+}
+
+func (s *schemaInterceptor) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	out, ok := s.schemas[workflow.GetInfo(ctx).WorkflowType.Name]
+	if !ok {
+		return next
+	}
+	i := &schemaWorkflowInbound{schema: out}
+	i.Next = next
+	return i
+}
+
+type schemaWorkflowInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+	schema *schema.Schema
+}
+
+func (w *schemaWorkflowInbound) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (interface{}, error) {
+	result, err := w.Next.ExecuteWorkflow(ctx, in)
+	if err != nil {
+		return result, err
+	}
+	if verr := w.schema.Validate("output", result); verr != nil {
+		workflow.GetLogger(ctx).Error("workflow output does not match its schema", "err", verr)
+		return nil, schema.OutputError(verr)
+	}
+	return result, nil
+}
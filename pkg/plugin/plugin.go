@@ -0,0 +1,57 @@
+// Package plugin 定义进程外活动插件：插件是独立的可执行文件，worker 启动时从插件目录发现并通过
+// hashicorp/go-plugin（gRPC）启动，插件提供的每个活动都注册为 worker 上的同名活动。
+//
+// 编写插件只需要本包和 pluginpb，不依赖 zebra-workflow 的其他代码：
+//
+//	func main() {
+//		plugin.Serve(plugin.Activity{
+//			Name: "Resize",
+//			Execute: func(ctx context.Context, req *plugin.Request) (interface{}, error) { ... },
+//		})
+//	}
+package plugin
+
+import (
+	"context"
+
+	goplugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"zebra-workflow/pkg/plugin/pluginpb"
+)
+
+// ProtocolVersion 协议版本，不兼容的修改需要递增
+const ProtocolVersion = 1
+
+// Name go-plugin 中的插件名
+const Name = "activity"
+
+// Handshake worker 和插件共同使用的握手配置；直接运行插件可执行文件时会提示它只能由 worker 启动
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  ProtocolVersion,
+	MagicCookieKey:   "ZEBRA_ACTIVITY_PLUGIN",
+	MagicCookieValue: "c1b5e2d4-activity-plugin",
+}
+
+// GRPCPlugin 实现 goplugin.GRPCPlugin：插件侧注册 Impl，worker 侧得到 pluginpb.ActivityPluginClient
+type GRPCPlugin struct {
+	goplugin.NetRPCUnsupportedPlugin
+	// Impl 仅插件侧设置
+	Impl pluginpb.ActivityPluginServer
+}
+
+var _ goplugin.GRPCPlugin = (*GRPCPlugin)(nil)
+
+func (p *GRPCPlugin) GRPCServer(_ *goplugin.GRPCBroker, s *grpc.Server) error {
+	pluginpb.RegisterActivityPluginServer(s, p.Impl)
+	return nil
+}
+
+func (p *GRPCPlugin) GRPCClient(_ context.Context, _ *goplugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return pluginpb.NewActivityPluginClient(conn), nil
+}
+
+// PluginSet worker 启动插件时使用的插件集合
+func PluginSet() goplugin.PluginSet {
+	return goplugin.PluginSet{Name: &GRPCPlugin{}}
+}
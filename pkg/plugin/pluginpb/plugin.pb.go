@@ -0,0 +1,818 @@
+// 活动插件协议：插件是独立的可执行文件，由 worker 通过 hashicorp/go-plugin 启动，
+// 经 gRPC 提供活动描述、执行（流式心跳）和取消。
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: pkg/plugin/pluginpb/plugin.proto
+
+package pluginpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DescribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeRequest) Reset() {
+	*x = DescribeRequest{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeRequest) ProtoMessage() {}
+
+func (x *DescribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeRequest.ProtoReflect.Descriptor instead.
+func (*DescribeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{0}
+}
+
+type DescribeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Activities    []*ActivityDescriptor  `protobuf:"bytes,1,rep,name=activities,proto3" json:"activities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeResponse) Reset() {
+	*x = DescribeResponse{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeResponse) ProtoMessage() {}
+
+func (x *DescribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeResponse.ProtoReflect.Descriptor instead.
+func (*DescribeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DescribeResponse) GetActivities() []*ActivityDescriptor {
+	if x != nil {
+		return x.Activities
+	}
+	return nil
+}
+
+type ActivityDescriptor struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name 注册到 worker 的活动类型名
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// input_schema / output_schema 为 JSON Schema 文本，可为空
+	InputSchema   string `protobuf:"bytes,3,opt,name=input_schema,json=inputSchema,proto3" json:"input_schema,omitempty"`
+	OutputSchema  string `protobuf:"bytes,4,opt,name=output_schema,json=outputSchema,proto3" json:"output_schema,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityDescriptor) Reset() {
+	*x = ActivityDescriptor{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityDescriptor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityDescriptor) ProtoMessage() {}
+
+func (x *ActivityDescriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityDescriptor.ProtoReflect.Descriptor instead.
+func (*ActivityDescriptor) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ActivityDescriptor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ActivityDescriptor) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ActivityDescriptor) GetInputSchema() string {
+	if x != nil {
+		return x.InputSchema
+	}
+	return ""
+}
+
+func (x *ActivityDescriptor) GetOutputSchema() string {
+	if x != nil {
+		return x.OutputSchema
+	}
+	return ""
+}
+
+type ActivityInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	TaskQueue     string                 `protobuf:"bytes,2,opt,name=task_queue,json=taskQueue,proto3" json:"task_queue,omitempty"`
+	WorkflowId    string                 `protobuf:"bytes,3,opt,name=workflow_id,json=workflowId,proto3" json:"workflow_id,omitempty"`
+	RunId         string                 `protobuf:"bytes,4,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	ActivityId    string                 `protobuf:"bytes,5,opt,name=activity_id,json=activityId,proto3" json:"activity_id,omitempty"`
+	Attempt       int32                  `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityInfo) Reset() {
+	*x = ActivityInfo{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityInfo) ProtoMessage() {}
+
+func (x *ActivityInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityInfo.ProtoReflect.Descriptor instead.
+func (*ActivityInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ActivityInfo) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ActivityInfo) GetTaskQueue() string {
+	if x != nil {
+		return x.TaskQueue
+	}
+	return ""
+}
+
+func (x *ActivityInfo) GetWorkflowId() string {
+	if x != nil {
+		return x.WorkflowId
+	}
+	return ""
+}
+
+func (x *ActivityInfo) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ActivityInfo) GetActivityId() string {
+	if x != nil {
+		return x.ActivityId
+	}
+	return ""
+}
+
+func (x *ActivityInfo) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+type ExecuteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// execution_id 由 worker 生成，Cancel 使用
+	ExecutionId string            `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	Activity    string            `protobuf:"bytes,2,opt,name=activity,proto3" json:"activity,omitempty"`
+	Input       map[string]string `protobuf:"bytes,3,rep,name=input,proto3" json:"input,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Info        *ActivityInfo     `protobuf:"bytes,4,opt,name=info,proto3" json:"info,omitempty"`
+	// heartbeat_details 上一次尝试最后一个心跳的 JSON，用于从断点继续；没有时为空
+	HeartbeatDetails []byte `protobuf:"bytes,5,opt,name=heartbeat_details,json=heartbeatDetails,proto3" json:"heartbeat_details,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ExecuteRequest) Reset() {
+	*x = ExecuteRequest{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteRequest) ProtoMessage() {}
+
+func (x *ExecuteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExecuteRequest) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetActivity() string {
+	if x != nil {
+		return x.Activity
+	}
+	return ""
+}
+
+func (x *ExecuteRequest) GetInput() map[string]string {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *ExecuteRequest) GetInfo() *ActivityInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *ExecuteRequest) GetHeartbeatDetails() []byte {
+	if x != nil {
+		return x.HeartbeatDetails
+	}
+	return nil
+}
+
+type ExecuteEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*ExecuteEvent_Heartbeat
+	//	*ExecuteEvent_Result
+	//	*ExecuteEvent_Error
+	Event         isExecuteEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteEvent) Reset() {
+	*x = ExecuteEvent{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteEvent) ProtoMessage() {}
+
+func (x *ExecuteEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteEvent.ProtoReflect.Descriptor instead.
+func (*ExecuteEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExecuteEvent) GetEvent() isExecuteEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *ExecuteEvent) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		if x, ok := x.Event.(*ExecuteEvent_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *ExecuteEvent) GetResult() *Result {
+	if x != nil {
+		if x, ok := x.Event.(*ExecuteEvent_Result); ok {
+			return x.Result
+		}
+	}
+	return nil
+}
+
+func (x *ExecuteEvent) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Event.(*ExecuteEvent_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isExecuteEvent_Event interface {
+	isExecuteEvent_Event()
+}
+
+type ExecuteEvent_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,1,opt,name=heartbeat,proto3,oneof"`
+}
+
+type ExecuteEvent_Result struct {
+	Result *Result `protobuf:"bytes,2,opt,name=result,proto3,oneof"`
+}
+
+type ExecuteEvent_Error struct {
+	Error *Error `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*ExecuteEvent_Heartbeat) isExecuteEvent_Event() {}
+
+func (*ExecuteEvent_Result) isExecuteEvent_Event() {}
+
+func (*ExecuteEvent_Error) isExecuteEvent_Event() {}
+
+type Heartbeat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// details JSON，重试时通过 heartbeat_details 传回
+	Details       []byte `protobuf:"bytes,1,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Heartbeat) GetDetails() []byte {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// json 活动结果（JSON）
+	Json          []byte `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Result) GetJson() []byte {
+	if x != nil {
+		return x.Json
+	}
+	return nil
+}
+
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// type 对应 ApplicationError 的类型
+	Type         string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Message      string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	NonRetryable bool   `protobuf:"varint,3,opt,name=non_retryable,json=nonRetryable,proto3" json:"non_retryable,omitempty"`
+	// details JSON，可为空
+	Details []byte `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	// next_retry_delay_ms 覆盖 RetryPolicy 的下次重试间隔，0 表示不覆盖
+	NextRetryDelayMs int64 `protobuf:"varint,5,opt,name=next_retry_delay_ms,json=nextRetryDelayMs,proto3" json:"next_retry_delay_ms,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Error) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetNonRetryable() bool {
+	if x != nil {
+		return x.NonRetryable
+	}
+	return false
+}
+
+func (x *Error) GetDetails() []byte {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *Error) GetNextRetryDelayMs() int64 {
+	if x != nil {
+		return x.NextRetryDelayMs
+	}
+	return 0
+}
+
+type CancelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionId   string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelRequest) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+type CancelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelResponse) Reset() {
+	*x = CancelResponse{}
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelResponse) ProtoMessage() {}
+
+func (x *CancelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_plugin_pluginpb_plugin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelResponse.ProtoReflect.Descriptor instead.
+func (*CancelResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP(), []int{10}
+}
+
+var File_pkg_plugin_pluginpb_plugin_proto protoreflect.FileDescriptor
+
+const file_pkg_plugin_pluginpb_plugin_proto_rawDesc = "" +
+	"\n" +
+	" pkg/plugin/pluginpb/plugin.proto\x12\x0fzebra.plugin.v1\"\x11\n" +
+	"\x0fDescribeRequest\"W\n" +
+	"\x10DescribeResponse\x12C\n" +
+	"\n" +
+	"activities\x18\x01 \x03(\v2#.zebra.plugin.v1.ActivityDescriptorR\n" +
+	"activities\"\x92\x01\n" +
+	"\x12ActivityDescriptor\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12!\n" +
+	"\finput_schema\x18\x03 \x01(\tR\vinputSchema\x12#\n" +
+	"\routput_schema\x18\x04 \x01(\tR\foutputSchema\"\xbe\x01\n" +
+	"\fActivityInfo\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x1d\n" +
+	"\n" +
+	"task_queue\x18\x02 \x01(\tR\ttaskQueue\x12\x1f\n" +
+	"\vworkflow_id\x18\x03 \x01(\tR\n" +
+	"workflowId\x12\x15\n" +
+	"\x06run_id\x18\x04 \x01(\tR\x05runId\x12\x1f\n" +
+	"\vactivity_id\x18\x05 \x01(\tR\n" +
+	"activityId\x12\x18\n" +
+	"\aattempt\x18\x06 \x01(\x05R\aattempt\"\xab\x02\n" +
+	"\x0eExecuteRequest\x12!\n" +
+	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\x12\x1a\n" +
+	"\bactivity\x18\x02 \x01(\tR\bactivity\x12@\n" +
+	"\x05input\x18\x03 \x03(\v2*.zebra.plugin.v1.ExecuteRequest.InputEntryR\x05input\x121\n" +
+	"\x04info\x18\x04 \x01(\v2\x1d.zebra.plugin.v1.ActivityInfoR\x04info\x12+\n" +
+	"\x11heartbeat_details\x18\x05 \x01(\fR\x10heartbeatDetails\x1a8\n" +
+	"\n" +
+	"InputEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb6\x01\n" +
+	"\fExecuteEvent\x12:\n" +
+	"\theartbeat\x18\x01 \x01(\v2\x1a.zebra.plugin.v1.HeartbeatH\x00R\theartbeat\x121\n" +
+	"\x06result\x18\x02 \x01(\v2\x17.zebra.plugin.v1.ResultH\x00R\x06result\x12.\n" +
+	"\x05error\x18\x03 \x01(\v2\x16.zebra.plugin.v1.ErrorH\x00R\x05errorB\a\n" +
+	"\x05event\"%\n" +
+	"\tHeartbeat\x12\x18\n" +
+	"\adetails\x18\x01 \x01(\fR\adetails\"\x1c\n" +
+	"\x06Result\x12\x12\n" +
+	"\x04json\x18\x01 \x01(\fR\x04json\"\xa3\x01\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12#\n" +
+	"\rnon_retryable\x18\x03 \x01(\bR\fnonRetryable\x12\x18\n" +
+	"\adetails\x18\x04 \x01(\fR\adetails\x12-\n" +
+	"\x13next_retry_delay_ms\x18\x05 \x01(\x03R\x10nextRetryDelayMs\"2\n" +
+	"\rCancelRequest\x12!\n" +
+	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\"\x10\n" +
+	"\x0eCancelResponse2\xf9\x01\n" +
+	"\x0eActivityPlugin\x12O\n" +
+	"\bDescribe\x12 .zebra.plugin.v1.DescribeRequest\x1a!.zebra.plugin.v1.DescribeResponse\x12K\n" +
+	"\aExecute\x12\x1f.zebra.plugin.v1.ExecuteRequest\x1a\x1d.zebra.plugin.v1.ExecuteEvent0\x01\x12I\n" +
+	"\x06Cancel\x12\x1e.zebra.plugin.v1.CancelRequest\x1a\x1f.zebra.plugin.v1.CancelResponseB$Z\"zebra-workflow/pkg/plugin/pluginpbb\x06proto3"
+
+var (
+	file_pkg_plugin_pluginpb_plugin_proto_rawDescOnce sync.Once
+	file_pkg_plugin_pluginpb_plugin_proto_rawDescData []byte
+)
+
+func file_pkg_plugin_pluginpb_plugin_proto_rawDescGZIP() []byte {
+	file_pkg_plugin_pluginpb_plugin_proto_rawDescOnce.Do(func() {
+		file_pkg_plugin_pluginpb_plugin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pkg_plugin_pluginpb_plugin_proto_rawDesc), len(file_pkg_plugin_pluginpb_plugin_proto_rawDesc)))
+	})
+	return file_pkg_plugin_pluginpb_plugin_proto_rawDescData
+}
+
+var file_pkg_plugin_pluginpb_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_pkg_plugin_pluginpb_plugin_proto_goTypes = []any{
+	(*DescribeRequest)(nil),    // 0: zebra.plugin.v1.DescribeRequest
+	(*DescribeResponse)(nil),   // 1: zebra.plugin.v1.DescribeResponse
+	(*ActivityDescriptor)(nil), // 2: zebra.plugin.v1.ActivityDescriptor
+	(*ActivityInfo)(nil),       // 3: zebra.plugin.v1.ActivityInfo
+	(*ExecuteRequest)(nil),     // 4: zebra.plugin.v1.ExecuteRequest
+	(*ExecuteEvent)(nil),       // 5: zebra.plugin.v1.ExecuteEvent
+	(*Heartbeat)(nil),          // 6: zebra.plugin.v1.Heartbeat
+	(*Result)(nil),             // 7: zebra.plugin.v1.Result
+	(*Error)(nil),              // 8: zebra.plugin.v1.Error
+	(*CancelRequest)(nil),      // 9: zebra.plugin.v1.CancelRequest
+	(*CancelResponse)(nil),     // 10: zebra.plugin.v1.CancelResponse
+	nil,                        // 11: zebra.plugin.v1.ExecuteRequest.InputEntry
+}
+var file_pkg_plugin_pluginpb_plugin_proto_depIdxs = []int32{
+	2,  // 0: zebra.plugin.v1.DescribeResponse.activities:type_name -> zebra.plugin.v1.ActivityDescriptor
+	11, // 1: zebra.plugin.v1.ExecuteRequest.input:type_name -> zebra.plugin.v1.ExecuteRequest.InputEntry
+	3,  // 2: zebra.plugin.v1.ExecuteRequest.info:type_name -> zebra.plugin.v1.ActivityInfo
+	6,  // 3: zebra.plugin.v1.ExecuteEvent.heartbeat:type_name -> zebra.plugin.v1.Heartbeat
+	7,  // 4: zebra.plugin.v1.ExecuteEvent.result:type_name -> zebra.plugin.v1.Result
+	8,  // 5: zebra.plugin.v1.ExecuteEvent.error:type_name -> zebra.plugin.v1.Error
+	0,  // 6: zebra.plugin.v1.ActivityPlugin.Describe:input_type -> zebra.plugin.v1.DescribeRequest
+	4,  // 7: zebra.plugin.v1.ActivityPlugin.Execute:input_type -> zebra.plugin.v1.ExecuteRequest
+	9,  // 8: zebra.plugin.v1.ActivityPlugin.Cancel:input_type -> zebra.plugin.v1.CancelRequest
+	1,  // 9: zebra.plugin.v1.ActivityPlugin.Describe:output_type -> zebra.plugin.v1.DescribeResponse
+	5,  // 10: zebra.plugin.v1.ActivityPlugin.Execute:output_type -> zebra.plugin.v1.ExecuteEvent
+	10, // 11: zebra.plugin.v1.ActivityPlugin.Cancel:output_type -> zebra.plugin.v1.CancelResponse
+	9,  // [9:12] is the sub-list for method output_type
+	6,  // [6:9] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_pkg_plugin_pluginpb_plugin_proto_init() }
+func file_pkg_plugin_pluginpb_plugin_proto_init() {
+	if File_pkg_plugin_pluginpb_plugin_proto != nil {
+		return
+	}
+	file_pkg_plugin_pluginpb_plugin_proto_msgTypes[5].OneofWrappers = []any{
+		(*ExecuteEvent_Heartbeat)(nil),
+		(*ExecuteEvent_Result)(nil),
+		(*ExecuteEvent_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_plugin_pluginpb_plugin_proto_rawDesc), len(file_pkg_plugin_pluginpb_plugin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_plugin_pluginpb_plugin_proto_goTypes,
+		DependencyIndexes: file_pkg_plugin_pluginpb_plugin_proto_depIdxs,
+		MessageInfos:      file_pkg_plugin_pluginpb_plugin_proto_msgTypes,
+	}.Build()
+	File_pkg_plugin_pluginpb_plugin_proto = out.File
+	file_pkg_plugin_pluginpb_plugin_proto_goTypes = nil
+	file_pkg_plugin_pluginpb_plugin_proto_depIdxs = nil
+}
@@ -0,0 +1,210 @@
+// 活动插件协议：插件是独立的可执行文件，由 worker 通过 hashicorp/go-plugin 启动，
+// 经 gRPC 提供活动描述、执行（流式心跳）和取消。
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pkg/plugin/pluginpb/plugin.proto
+
+package pluginpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ActivityPlugin_Describe_FullMethodName = "/zebra.plugin.v1.ActivityPlugin/Describe"
+	ActivityPlugin_Execute_FullMethodName  = "/zebra.plugin.v1.ActivityPlugin/Execute"
+	ActivityPlugin_Cancel_FullMethodName   = "/zebra.plugin.v1.ActivityPlugin/Cancel"
+)
+
+// ActivityPluginClient is the client API for ActivityPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ActivityPluginClient interface {
+	// Describe 返回插件提供的全部活动，worker 启动时调用一次
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error)
+	// Execute 执行一次活动：插件在流上发送任意个 Heartbeat，最后发送一个 Result 或 Error
+	Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecuteEvent], error)
+	// Cancel 取消 execution_id 对应的执行（活动被取消、超时或 worker 停止时调用）
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
+}
+
+type activityPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewActivityPluginClient(cc grpc.ClientConnInterface) ActivityPluginClient {
+	return &activityPluginClient{cc}
+}
+
+func (c *activityPluginClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DescribeResponse)
+	err := c.cc.Invoke(ctx, ActivityPlugin_Describe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *activityPluginClient) Execute(ctx context.Context, in *ExecuteRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExecuteEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ActivityPlugin_ServiceDesc.Streams[0], ActivityPlugin_Execute_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecuteRequest, ExecuteEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ActivityPlugin_ExecuteClient = grpc.ServerStreamingClient[ExecuteEvent]
+
+func (c *activityPluginClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelResponse)
+	err := c.cc.Invoke(ctx, ActivityPlugin_Cancel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ActivityPluginServer is the server API for ActivityPlugin service.
+// All implementations must embed UnimplementedActivityPluginServer
+// for forward compatibility.
+type ActivityPluginServer interface {
+	// Describe 返回插件提供的全部活动，worker 启动时调用一次
+	Describe(context.Context, *DescribeRequest) (*DescribeResponse, error)
+	// Execute 执行一次活动：插件在流上发送任意个 Heartbeat，最后发送一个 Result 或 Error
+	Execute(*ExecuteRequest, grpc.ServerStreamingServer[ExecuteEvent]) error
+	// Cancel 取消 execution_id 对应的执行（活动被取消、超时或 worker 停止时调用）
+	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
+	mustEmbedUnimplementedActivityPluginServer()
+}
+
+// UnimplementedActivityPluginServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedActivityPluginServer struct{}
+
+func (UnimplementedActivityPluginServer) Describe(context.Context, *DescribeRequest) (*DescribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Describe not implemented")
+}
+func (UnimplementedActivityPluginServer) Execute(*ExecuteRequest, grpc.ServerStreamingServer[ExecuteEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedActivityPluginServer) Cancel(context.Context, *CancelRequest) (*CancelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (UnimplementedActivityPluginServer) mustEmbedUnimplementedActivityPluginServer() {}
+func (UnimplementedActivityPluginServer) testEmbeddedByValue()                        {}
+
+// UnsafeActivityPluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ActivityPluginServer will
+// result in compilation errors.
+type UnsafeActivityPluginServer interface {
+	mustEmbedUnimplementedActivityPluginServer()
+}
+
+func RegisterActivityPluginServer(s grpc.ServiceRegistrar, srv ActivityPluginServer) {
+	// If the following call pancis, it indicates UnimplementedActivityPluginServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ActivityPlugin_ServiceDesc, srv)
+}
+
+func _ActivityPlugin_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityPluginServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActivityPlugin_Describe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityPluginServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActivityPlugin_Execute_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActivityPluginServer).Execute(m, &grpc.GenericServerStream[ExecuteRequest, ExecuteEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ActivityPlugin_ExecuteServer = grpc.ServerStreamingServer[ExecuteEvent]
+
+func _ActivityPlugin_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityPluginServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActivityPlugin_Cancel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityPluginServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ActivityPlugin_ServiceDesc is the grpc.ServiceDesc for ActivityPlugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ActivityPlugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "zebra.plugin.v1.ActivityPlugin",
+	HandlerType: (*ActivityPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _ActivityPlugin_Describe_Handler,
+		},
+		{
+			MethodName: "Cancel",
+			Handler:    _ActivityPlugin_Cancel_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Execute",
+			Handler:       _ActivityPlugin_Execute_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/plugin/pluginpb/plugin.proto",
+}
@@ -0,0 +1,192 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	goplugin "github.com/hashicorp/go-plugin"
+
+	"zebra-workflow/pkg/plugin/pluginpb"
+)
+
+// Activity 插件提供的一个活动
+type Activity struct {
+	Name        string
+	Description string
+	// InputSchema / OutputSchema JSON Schema，可为空
+	InputSchema  json.RawMessage
+	OutputSchema json.RawMessage
+	// Execute 返回值按 JSON 序列化后作为活动结果；返回 *Error 可指定错误类型、是否重试和详情，
+	// 其他错误按可重试的 PluginError 处理。ctx 在活动被取消时结束
+	Execute func(ctx context.Context, req *Request) (interface{}, error)
+}
+
+// Request 一次活动执行的输入
+type Request struct {
+	Input map[string]string
+	Info  *pluginpb.ActivityInfo
+	// HeartbeatDetails 上一次尝试最后一个心跳的 JSON，首次执行时为空
+	HeartbeatDetails json.RawMessage
+
+	send func(*pluginpb.ExecuteEvent) error
+}
+
+// Heartbeat 发送心跳，details 按 JSON 序列化，重试时通过 HeartbeatDetails 传回。
+// 长时间运行的活动应定期调用，worker 才能及时收到取消
+func (r *Request) Heartbeat(details interface{}) error {
+	var b []byte
+	if details != nil {
+		var err error
+		if b, err = json.Marshal(details); err != nil {
+			return fmt.Errorf("plugin: marshal heartbeat details: %w", err)
+		}
+	}
+	return r.send(&pluginpb.ExecuteEvent{Event: &pluginpb.ExecuteEvent_Heartbeat{Heartbeat: &pluginpb.Heartbeat{Details: b}}})
+}
+
+// Error 插件返回的活动错误，对应 Temporal ApplicationError
+type Error struct {
+	Type         string
+	Message      string
+	NonRetryable bool
+	Details      interface{}
+	// NextRetryDelay 覆盖 RetryPolicy 的下次重试间隔
+	NextRetryDelay time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Serve 在插件进程中提供 activities，直到 worker 关闭插件；由插件的 main 调用
+func Serve(activities ...Activity) {
+	goplugin.Serve(&goplugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		Plugins:         goplugin.PluginSet{Name: &GRPCPlugin{Impl: NewServer(activities...)}},
+		GRPCServer:      goplugin.DefaultGRPCServer,
+	})
+}
+
+// server 实现 pluginpb.ActivityPluginServer
+type server struct {
+	pluginpb.UnimplementedActivityPluginServer
+
+	activities map[string]Activity
+	order      []string
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+// NewServer 返回提供 activities 的 gRPC 服务，Serve 使用；也可用于测试插件
+func NewServer(activities ...Activity) pluginpb.ActivityPluginServer {
+	s := &server{activities: make(map[string]Activity, len(activities)), running: make(map[string]context.CancelFunc)}
+	for _, a := range activities {
+		if a.Name == "" || a.Execute == nil {
+			panic("plugin: activity without name or Execute")
+		}
+		if _, ok := s.activities[a.Name]; ok {
+			panic("plugin: duplicate activity " + a.Name)
+		}
+		s.activities[a.Name] = a
+		s.order = append(s.order, a.Name)
+	}
+	return s
+}
+
+func (s *server) Describe(context.Context, *pluginpb.DescribeRequest) (*pluginpb.DescribeResponse, error) {
+	resp := &pluginpb.DescribeResponse{}
+	for _, name := range s.order {
+		a := s.activities[name]
+		resp.Activities = append(resp.Activities, &pluginpb.ActivityDescriptor{
+			Name:         a.Name,
+			Description:  a.Description,
+			InputSchema:  string(a.InputSchema),
+			OutputSchema: string(a.OutputSchema),
+		})
+	}
+	return resp, nil
+}
+
+func (s *server) Execute(req *pluginpb.ExecuteRequest, stream pluginpb.ActivityPlugin_ExecuteServer) error {
+	a, ok := s.activities[req.GetActivity()]
+	if !ok {
+		return stream.Send(errorEvent(&Error{Type: "PluginActivityNotFound", Message: "unknown activity " + req.GetActivity(), NonRetryable: true}))
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	s.mu.Lock()
+	s.running[req.GetExecutionId()] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, req.GetExecutionId())
+		s.mu.Unlock()
+	}()
+
+	// 活动可能在多个 goroutine 中发送心跳，gRPC 流的 Send 不能并发调用
+	var sendMu sync.Mutex
+	r := &Request{
+		Input:            req.GetInput(),
+		Info:             req.GetInfo(),
+		HeartbeatDetails: req.GetHeartbeatDetails(),
+		send: func(ev *pluginpb.ExecuteEvent) error {
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			return stream.Send(ev)
+		},
+	}
+	result, err := runActivity(ctx, a, r)
+	if err != nil {
+		var perr *Error
+		if !errors.As(err, &perr) {
+			perr = &Error{Type: "PluginError", Message: err.Error()}
+		}
+		return r.send(errorEvent(perr))
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return r.send(errorEvent(&Error{Type: "PluginError", Message: "marshal result: " + err.Error(), NonRetryable: true}))
+	}
+	return r.send(&pluginpb.ExecuteEvent{Event: &pluginpb.ExecuteEvent_Result{Result: &pluginpb.Result{Json: b}}})
+}
+
+// runActivity 执行活动，panic 转换为错误，避免整个插件进程退出
+func runActivity(ctx context.Context, a Activity, r *Request) (result interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = &Error{Type: "PluginPanic", Message: fmt.Sprintf("activity %s panicked: %v", a.Name, p)}
+		}
+	}()
+	return a.Execute(ctx, r)
+}
+
+func (s *server) Cancel(_ context.Context, req *pluginpb.CancelRequest) (*pluginpb.CancelResponse, error) {
+	s.mu.Lock()
+	cancel, ok := s.running[req.GetExecutionId()]
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return &pluginpb.CancelResponse{}, nil
+}
+
+func errorEvent(e *Error) *pluginpb.ExecuteEvent {
+	pe := &pluginpb.Error{
+		Type:             e.Type,
+		Message:          e.Message,
+		NonRetryable:     e.NonRetryable,
+		NextRetryDelayMs: e.NextRetryDelay.Milliseconds(),
+	}
+	if e.Details != nil {
+		if b, err := json.Marshal(e.Details); err == nil {
+			pe.Details = b
+		}
+	}
+	return &pluginpb.ExecuteEvent{Event: &pluginpb.ExecuteEvent_Error{Error: pe}}
+}